@@ -2,6 +2,7 @@ package dynamodb
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -9,7 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 )
 
-// Operator DynamoDB operators for string and numbers
+// Operator DynamoDB comparison operators and functions used in conditions and key conditions
 type Operator int
 
 const (
@@ -23,31 +24,114 @@ const (
 	GT
 	// GE greater or equal
 	GE
-	// BETWEEN upper and lower
+	// BETWEEN upper and lower, the value should be Range or FromToDate
 	BETWEEN
+	// NE not equal
+	NE
+	// IN equal to any of the values, the value should be a slice eg. []string{"a", "b"}
+	IN
+	// BEGINSWITH the string attribute starts with the value, supported in key conditions
+	BEGINSWITH
+	// CONTAINS the string attribute contains the value, or the set attribute contains the element
+	CONTAINS
+	// EXISTS the attribute exists, the value is ignored
+	EXISTS
+	// NOTEXISTS the attribute does not exist, the value is ignored
+	NOTEXISTS
+	// ATTRTYPE the attribute is of the provided dynamodb type eg. expression.StringSet or "SS"
+	ATTRTYPE
+	// SIZE compares the size of the attribute, the value should be SizeCondition
+	SIZE
 )
 
+// operatorNames the operator names used in error messages
+var operatorNames = map[Operator]string{
+	EQUAL:      "EQUAL",
+	LT:         "LT",
+	LE:         "LE",
+	GT:         "GT",
+	GE:         "GE",
+	BETWEEN:    "BETWEEN",
+	NE:         "NE",
+	IN:         "IN",
+	BEGINSWITH: "BEGINSWITH",
+	CONTAINS:   "CONTAINS",
+	EXISTS:     "EXISTS",
+	NOTEXISTS:  "NOTEXISTS",
+	ATTRTYPE:   "ATTRTYPE",
+	SIZE:       "SIZE",
+}
+
+// String returns the operator name
+func (o Operator) String() string {
+	if name, ok := operatorNames[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("Operator(%d)", int(o))
+}
+
 // FromToDate which to be used in constructing the between operations for date
 type FromToDate struct {
 	FromDate uint64
 	ToDate   uint64
 }
 
+// ReturnValue defines the item attributes returned by an update
+type ReturnValue string
+
+const (
+	// ReturnNone nothing is returned
+	ReturnNone ReturnValue = dynamodb.ReturnValueNone
+	// ReturnAllOld all the attributes of the item before the update
+	ReturnAllOld ReturnValue = dynamodb.ReturnValueAllOld
+	// ReturnUpdatedOld the updated attributes before the update
+	ReturnUpdatedOld ReturnValue = dynamodb.ReturnValueUpdatedOld
+	// ReturnAllNew all the attributes of the item after the update
+	ReturnAllNew ReturnValue = dynamodb.ReturnValueAllNew
+	// ReturnUpdatedNew the updated attributes after the update
+	ReturnUpdatedNew ReturnValue = dynamodb.ReturnValueUpdatedNew
+)
+
+// Range the lower and upper bounds of BETWEEN for any comparable values eg. numbers or strings
+type Range struct {
+	From interface{}
+	To   interface{}
+}
+
+// SizeCondition compares the size of an attribute eg. SizeCondition{Operator: GT, Value: 3}
+// the operator should be a comparison operator, BETWEEN or IN along with the matching value
+type SizeCondition struct {
+	Operator Operator
+	Value    interface{}
+}
+
 // AwsExpressionWrapper ...
 type AwsExpressionWrapper struct {
 	updateExpression    expression.UpdateBuilder
 	conditionExpression expression.ConditionBuilder
 	keyCondition        expression.KeyConditionBuilder
-	projection          expression.ProjectionBuilder
-	partitionKeyValue   *dynamodb.AttributeValue
-	sortKeyValue        *dynamodb.AttributeValue
-	exclusiveStartKey   map[string]*dynamodb.AttributeValue
-	scanIndexForward    *bool
-	partitionKeyName    string
-	sortKeyName         string
-	dynamoDBTable       string
-	dynamoDBIndex       string
-	limit               *int64
+	// keyFilter the key condition as a filter, applied by the scans which do not support key conditions
+	keyFilter         expression.ConditionBuilder
+	projection        expression.ProjectionBuilder
+	partitionKeyValue *dynamodb.AttributeValue
+	sortKeyValue      *dynamodb.AttributeValue
+	exclusiveStartKey map[string]*dynamodb.AttributeValue
+	scanIndexForward  *bool
+	partitionKeyName  string
+	sortKeyName       string
+	dynamoDBTable     string
+	dynamoDBIndex     string
+	cursor            string
+	cursorSecret      []byte
+	returnValues      ReturnValue
+	consistentRead    *bool
+	limit             *int64
+	segment           *int64
+	totalSegments     *int64
+	// version the optimistic locking check of the update, set by WithVersion
+	version *versionCheck
+	// err holds the first invalid condition, it is returned when building the input
+	err error
 }
 
 // NewExpressionWrapper creates new expression wrapper
@@ -84,22 +168,97 @@ func (expr *AwsExpressionWrapper) WithProjection(fields ...string) *AwsExpressio
 	return expr
 }
 
+// WithConsistentRead requests a strongly consistent read instead of the default eventually consistent one,
+// the handler rejects consistent reads of global secondary indexes
+func (expr *AwsExpressionWrapper) WithConsistentRead(consistent bool) *AwsExpressionWrapper {
+	expr.consistentRead = aws.Bool(consistent)
+	return expr
+}
+
 // WithUpdateField sets update expression value for a specific field name
+// nested attributes are addressed using document paths eg. address.city or tags[0]
 func (expr *AwsExpressionWrapper) WithUpdateField(name string, value interface{}) *AwsExpressionWrapper {
-	if reflect.DeepEqual(expr.updateExpression, expression.UpdateBuilder{}) {
-		expr.updateExpression = expression.Set(
-			expression.Name(name),
-			expression.Value(value),
-		)
-		return expr
-	}
-	expr.updateExpression.Set(
+	expr.updateExpression = expr.updateExpression.Set(
 		expression.Name(name),
 		expression.Value(value),
 	)
 	return expr
 }
 
+// WithSetIfNotExists sets the attribute only if it does not exist yet: SET name = if_not_exists(name, value)
+func (expr *AwsExpressionWrapper) WithSetIfNotExists(name string, value interface{}) *AwsExpressionWrapper {
+	expr.updateExpression = expr.updateExpression.Set(
+		expression.Name(name),
+		expression.Name(name).IfNotExists(expression.Value(value)),
+	)
+	return expr
+}
+
+// WithIncrement atomically adds the value to a number attribute using ADD, a missing attribute is created
+func (expr *AwsExpressionWrapper) WithIncrement(name string, by interface{}) *AwsExpressionWrapper {
+	expr.updateExpression = expr.updateExpression.Add(
+		expression.Name(name),
+		expression.Value(by),
+	)
+	return expr
+}
+
+// WithDecrement atomically subtracts the value from a number attribute, a missing attribute is treated as 0
+func (expr *AwsExpressionWrapper) WithDecrement(name string, by interface{}) *AwsExpressionWrapper {
+	expr.updateExpression = expr.updateExpression.Set(
+		expression.Name(name),
+		expression.Minus(expression.Name(name).IfNotExists(expression.Value(0)), expression.Value(by)),
+	)
+	return expr
+}
+
+// WithListAppend appends the values to a list attribute, a missing attribute is created
+// values can be a slice or a single value
+func (expr *AwsExpressionWrapper) WithListAppend(name string, values interface{}) *AwsExpressionWrapper {
+	emptyList := &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
+	expr.updateExpression = expr.updateExpression.Set(
+		expression.Name(name),
+		expression.ListAppend(expression.Name(name).IfNotExists(expression.Value(emptyList)), expression.Value(listValue(values))),
+	)
+	return expr
+}
+
+// WithAddToSet adds the elements to a string, number or binary set attribute, a missing attribute is created
+// values should be a slice of strings, numbers or byte slices eg. []string{"a", "b"}
+func (expr *AwsExpressionWrapper) WithAddToSet(name string, values interface{}) *AwsExpressionWrapper {
+	set, err := setValue(values)
+	if err != nil {
+		return expr.withErr(validationErr(name, err))
+	}
+	expr.updateExpression = expr.updateExpression.Add(expression.Name(name), expression.Value(set))
+	return expr
+}
+
+// WithDeleteFromSet removes the elements from a string, number or binary set attribute
+// values should be a slice of strings, numbers or byte slices eg. []string{"a", "b"}
+func (expr *AwsExpressionWrapper) WithDeleteFromSet(name string, values interface{}) *AwsExpressionWrapper {
+	set, err := setValue(values)
+	if err != nil {
+		return expr.withErr(validationErr(name, err))
+	}
+	expr.updateExpression = expr.updateExpression.Delete(expression.Name(name), expression.Value(set))
+	return expr
+}
+
+// WithRemove removes the attributes from the item
+func (expr *AwsExpressionWrapper) WithRemove(names ...string) *AwsExpressionWrapper {
+	for _, name := range names {
+		expr.updateExpression = expr.updateExpression.Remove(expression.Name(name))
+	}
+	return expr
+}
+
+// WithReturnValues defines the item attributes returned by the update eg. ReturnAllNew
+func (expr *AwsExpressionWrapper) WithReturnValues(returnValues ReturnValue) *AwsExpressionWrapper {
+	expr.returnValues = returnValues
+	return expr
+}
+
 // WithLimit sets the maximum number of items to evaluate
 func (expr *AwsExpressionWrapper) WithLimit(limit int64) *AwsExpressionWrapper {
 	expr.limit = aws.Int64(limit)
@@ -110,7 +269,11 @@ func (expr *AwsExpressionWrapper) WithLimit(limit int64) *AwsExpressionWrapper {
 func (expr *AwsExpressionWrapper) WithCondition(
 	name string, value interface{}, operator Operator,
 ) *AwsExpressionWrapper {
-	expr.conditionExpression = createCondition(name, value, operator)
+	condition, err := createCondition(name, value, operator)
+	if err != nil {
+		return expr.withErr(validationErr(name, err))
+	}
+	expr.conditionExpression = condition
 	return expr
 }
 
@@ -123,7 +286,10 @@ func (expr *AwsExpressionWrapper) AndCondition(
 		expr.WithCondition(name, value, operator)
 		return expr
 	}
-	condition := createCondition(name, value, operator)
+	condition, err := createCondition(name, value, operator)
+	if err != nil {
+		return expr.withErr(validationErr(name, err))
+	}
 	newConditionExpr := expr.conditionExpression.And(condition)
 	expr.conditionExpression = newConditionExpr
 	return expr
@@ -138,18 +304,63 @@ func (expr *AwsExpressionWrapper) OrCondition(
 		expr.WithCondition(name, value, operator)
 		return expr
 	}
-	condition := createCondition(name, value, operator)
+	condition, err := createCondition(name, value, operator)
+	if err != nil {
+		return expr.withErr(validationErr(name, err))
+	}
 	newConditionExpr := expr.conditionExpression.Or(condition)
 	expr.conditionExpression = newConditionExpr
 	return expr
 }
 
+// WithConditionGroup sets the initial condition out of a condition created using Cond
+func (expr *AwsExpressionWrapper) WithConditionGroup(condition Condition) *AwsExpressionWrapper {
+	if condition.err != nil {
+		return expr.withErr(condition.err)
+	}
+	expr.conditionExpression = condition.builder
+	return expr
+}
+
+// AndConditionGroup adds to the initial condition the condition group using AND if exists or create new condition
+func (expr *AwsExpressionWrapper) AndConditionGroup(condition Condition) *AwsExpressionWrapper {
+	if condition.err != nil {
+		return expr.withErr(condition.err)
+	}
+	if reflect.DeepEqual(expr.conditionExpression, expression.ConditionBuilder{}) {
+		return expr.WithConditionGroup(condition)
+	}
+	expr.conditionExpression = expr.conditionExpression.And(condition.builder)
+	return expr
+}
+
+// OrConditionGroup adds to the initial condition the condition group using OR if exists or create new condition
+func (expr *AwsExpressionWrapper) OrConditionGroup(condition Condition) *AwsExpressionWrapper {
+	if condition.err != nil {
+		return expr.withErr(condition.err)
+	}
+	if reflect.DeepEqual(expr.conditionExpression, expression.ConditionBuilder{}) {
+		return expr.WithConditionGroup(condition)
+	}
+	expr.conditionExpression = expr.conditionExpression.Or(condition.builder)
+	return expr
+}
+
 // WithKeyCondition sets the initial key condition
 // first key should always be using EQUAL operator as it represents the partition key
 func (expr *AwsExpressionWrapper) WithKeyCondition(
 	name string, value interface{}, operator Operator,
 ) *AwsExpressionWrapper {
-	expr.keyCondition = createKeyCondition(name, value, operator)
+	keyCondition, err := createKeyCondition(name, value, operator)
+	if err != nil {
+		return expr.withErr(validationErr(name, err))
+	}
+	keyFilter, err := createCondition(name, value, operator)
+	if err != nil {
+		return expr.withErr(validationErr(name, err))
+	}
+	expr.keyCondition = keyCondition
+	expr.keyFilter = keyFilter
 	return expr
 }
 
@@ -163,40 +374,77 @@ func (expr *AwsExpressionWrapper) AndKeyCondition(
 		return expr
 	}
 	cond1 := expr.keyCondition
-	cond2 := createKeyCondition(name, value, operator)
+	cond2, err := createKeyCondition(name, value, operator)
+	if err != nil {
+		return expr.withErr(validationErr(name, err))
+	}
+	keyFilter, err := createCondition(name, value, operator)
+	if err != nil {
+		return expr.withErr(validationErr(name, err))
+	}
 	expr.keyCondition = expression.KeyAnd(cond1, cond2)
+	expr.keyFilter = expr.keyFilter.And(keyFilter)
 	return expr
 }
 
-// WithPartitionKey adds partition key
+// WithPartitionKey adds a string partition key
 func (expr *AwsExpressionWrapper) WithPartitionKey(pKey string, pValue string) *AwsExpressionWrapper {
+	return expr.WithTypedPartitionKey(pKey, DBKeyValue(pValue), KeyTypeString)
+}
+
+// WithTypedPartitionKey adds a partition key of the provided scalar type
+func (expr *AwsExpressionWrapper) WithTypedPartitionKey(pKey string, pValue DBKeyValue, keyType DBKeyType) *AwsExpressionWrapper {
 	expr.partitionKeyName = pKey
 	if len(pValue) > 0 {
-		expr.partitionKeyValue = &dynamodb.AttributeValue{S: aws.String(pValue)}
+		expr.partitionKeyValue = keyType.AttributeValue(pValue)
 	}
 	return expr
 }
 
-// WithSortingKey adds sorting key if available
+// WithSortingKey adds a string sorting key if available
 func (expr *AwsExpressionWrapper) WithSortingKey(sKey string, sValue string) *AwsExpressionWrapper {
+	return expr.WithTypedSortingKey(sKey, DBKeyValue(sValue), KeyTypeString)
+}
+
+// WithTypedSortingKey adds a sorting key of the provided scalar type if available
+func (expr *AwsExpressionWrapper) WithTypedSortingKey(sKey string, sValue DBKeyValue, keyType DBKeyType) *AwsExpressionWrapper {
 	expr.sortKeyName = sKey
 	if len(sValue) > 0 {
-		expr.sortKeyValue = &dynamodb.AttributeValue{S: aws.String(sValue)}
+		expr.sortKeyValue = keyType.AttributeValue(sValue)
 	}
 	return expr
 }
 
-// WithLastEvaluatedKey defines the last evaluated key
-func (expr *AwsExpressionWrapper) WithLastEvaluatedKey(pKeyName, pKeyVal string, sKeyName, sKeyVal *string) *AwsExpressionWrapper {
-	lastEvaluatedKey := make(map[string]*dynamodb.AttributeValue)
-	lastEvaluatedKey[pKeyName] = &dynamodb.AttributeValue{
-		S: aws.String(pKeyVal),
+// WithKeys adds the partition and the sorting key if available
+// using the attribute names and the scalar types of the table or index keys
+func (expr *AwsExpressionWrapper) WithKeys(keyNames DBPSKeyNames, keys DBPSKeyValues) *AwsExpressionWrapper {
+	expr.WithTypedPartitionKey(string(keyNames.PartitionKey), keys.GetPartitionKey(), keyNames.PartitionKeyType)
+	if keyNames.SortKey != nil && keys.GetSortKey() != nil {
+		expr.WithTypedSortingKey(string(*keyNames.SortKey), *keys.GetSortKey(), keyNames.SortKeyType)
 	}
+	return expr
+}
 
+// WithLastEvaluatedKey defines the last evaluated key for string keys
+func (expr *AwsExpressionWrapper) WithLastEvaluatedKey(pKeyName, pKeyVal string, sKeyName, sKeyVal *string) *AwsExpressionWrapper {
+	keyNames := DBPSKeyNames{PartitionKey: DBKeyName(pKeyName)}
+	var sortKey *DBKeyValue
 	if sKeyName != nil && sKeyVal != nil {
-		lastEvaluatedKey[*sKeyName] = &dynamodb.AttributeValue{
-			S: aws.String(*sKeyVal),
-		}
+		name := DBKeyName(*sKeyName)
+		value := DBKeyValue(*sKeyVal)
+		keyNames.SortKey = &name
+		sortKey = &value
+	}
+	return expr.WithTypedLastEvaluatedKey(keyNames, NewDbPSKeyValues(DBKeyValue(pKeyVal), sortKey))
+}
+
+// WithTypedLastEvaluatedKey defines the last evaluated key using the names and the scalar types of the table or index keys
+func (expr *AwsExpressionWrapper) WithTypedLastEvaluatedKey(keyNames DBPSKeyNames, keys DBPSKeyValues) *AwsExpressionWrapper {
+	lastEvaluatedKey := make(map[string]*dynamodb.AttributeValue)
+	lastEvaluatedKey[string(keyNames.PartitionKey)] = keyNames.PartitionKeyType.AttributeValue(keys.GetPartitionKey())
+
+	if keyNames.SortKey != nil && keys.GetSortKey() != nil {
+		lastEvaluatedKey[string(*keyNames.SortKey)] = keyNames.SortKeyType.AttributeValue(*keys.GetSortKey())
 	}
 
 	expr.exclusiveStartKey = lastEvaluatedKey
@@ -217,31 +465,70 @@ func (expr *AwsExpressionWrapper) WithExlusiveStartingKey(lastEvaluatedKey map[s
 	return expr
 }
 
+// WithCursor sets the starting point of a query or a scan out of a cursor returned by a previous call
+// the cursor is rejected at build time if it was tampered with or issued for a different table or index
+func (expr *AwsExpressionWrapper) WithCursor(cursor string) *AwsExpressionWrapper {
+	expr.cursor = cursor
+	return expr
+}
+
+// WithSegment sets the segment to be read by a parallel scan and the total number of segments
+func (expr *AwsExpressionWrapper) WithSegment(segment, totalSegments int64) *AwsExpressionWrapper {
+	expr.segment = aws.Int64(segment)
+	expr.totalSegments = aws.Int64(totalSegments)
+	return expr
+}
+
 // BuildUpdateInput build the update input out of the update expression
 func (expr *AwsExpressionWrapper) BuildUpdateInput() (*dynamodb.UpdateItemInput, error) {
-	if reflect.DeepEqual(expr.updateExpression, expression.UpdateBuilder{}) {
-		return nil, errors.New("their is nothing set to be updated, please use WithUpdateField")
+	awsExpressionBuilder, err := expr.buildUpdate()
+	if err != nil {
+		return nil, err
 	}
 
 	keys, keyErr := expr.CreateQueryKeys()
 	if keyErr != nil {
 		return nil, keyErr
 	}
-
-	builder := expression.NewBuilder().WithUpdate(expr.updateExpression)
-
-	awsExpressionBuilder, err := builder.Build()
-	return &dynamodb.UpdateItemInput{
+	input := &dynamodb.UpdateItemInput{
 		ExpressionAttributeNames:  awsExpressionBuilder.Names(),
 		ExpressionAttributeValues: awsExpressionBuilder.Values(),
 		UpdateExpression:          awsExpressionBuilder.Update(),
+		ConditionExpression:       awsExpressionBuilder.Condition(),
 		Key:                       keys,
 		TableName:                 aws.String(expr.dynamoDBTable),
-	}, err
+	}
+	if len(expr.returnValues) > 0 {
+		input.ReturnValues = aws.String(string(expr.returnValues))
+	}
+	return input, nil
+}
+
+// buildUpdate builds the update expression along with the condition and the version check if any
+func (expr *AwsExpressionWrapper) buildUpdate() (expression.Expression, error) {
+	if expr.err != nil {
+		return expression.Expression{}, expr.err
+	}
+	if reflect.DeepEqual(expr.updateExpression, expression.UpdateBuilder{}) {
+		return expression.Expression{}, newValidationErr(
+			"update", "their is nothing set to be updated, please use WithUpdateField or the other update methods",
+		)
+	}
+
+	builder := expression.NewBuilder().WithUpdate(expr.updateExpression)
+	// the update is applied only if the condition is matched eg. attribute_exists or a version match
+	condition := withVersionCondition(expr.conditionExpression, expr.version)
+	if !reflect.DeepEqual(condition, expression.ConditionBuilder{}) {
+		builder = builder.WithCondition(condition)
+	}
+	return builder.Build()
 }
 
 // BuildQueryInput builds the expression and return the input to be used for the get
 func (expr *AwsExpressionWrapper) BuildQueryInput() (*dynamodb.QueryInput, error) {
+	if expr.err != nil {
+		return nil, expr.err
+	}
 	builder := expression.NewBuilder()
 	// check for available condition
 	if !reflect.DeepEqual(expr.conditionExpression, expression.ConditionBuilder{}) {
@@ -280,6 +567,7 @@ func (expr *AwsExpressionWrapper) BuildQueryInput() (*dynamodb.QueryInput, error
 	if len(expr.dynamoDBIndex) > 0 {
 		input.IndexName = aws.String(expr.dynamoDBIndex)
 	}
+	input.ConsistentRead = expr.consistentRead
 
 	if expr.scanIndexForward != nil {
 		input.ScanIndexForward = expr.scanIndexForward
@@ -293,59 +581,96 @@ func (expr *AwsExpressionWrapper) BuildQueryInput() (*dynamodb.QueryInput, error
 		input.ExclusiveStartKey = expr.exclusiveStartKey
 	}
 
+	if len(expr.cursor) > 0 {
+		startKey, err := decodeCursor(expr.cursor, expr.dynamoDBTable, expr.dynamoDBIndex, expr.cursorSecret)
+		if err != nil {
+			return nil, err
+		}
+		input.ExclusiveStartKey = startKey
+	}
+
 	return &input, nil
 }
 
 // BuildScanInput create scan query expression
 func (expr *AwsExpressionWrapper) BuildScanInput() (*dynamodb.ScanInput, error) {
 	if len(expr.dynamoDBTable) == 0 {
-		return nil, errors.New("missing table-name")
+		return nil, newValidationErr(tableNameField, "missing table-name")
+	}
+	if expr.err != nil {
+		return nil, expr.err
 	}
 	input := dynamodb.ScanInput{
 		TableName: aws.String(expr.dynamoDBTable),
 	}
 
 	builder := expression.NewBuilder()
-	// check for available condition
-	if !reflect.DeepEqual(expr.conditionExpression, expression.ConditionBuilder{}) {
-		builder = builder.WithFilter(expr.conditionExpression)
-		awsExpressionBuilder, _ := builder.Build()
-
-		input = dynamodb.ScanInput{
-			ExpressionAttributeNames:  awsExpressionBuilder.Names(),
-			ExpressionAttributeValues: awsExpressionBuilder.Values(),
-			FilterExpression:          awsExpressionBuilder.Filter(),
-			TableName:                 aws.String(expr.dynamoDBTable),
+	filter := expr.conditionExpression
+	// the key condition is applied along with the filter as scan does not support key conditions
+	if !reflect.DeepEqual(expr.keyFilter, expression.ConditionBuilder{}) {
+		if reflect.DeepEqual(filter, expression.ConditionBuilder{}) {
+			filter = expr.keyFilter
+		} else {
+			filter = expr.keyFilter.And(filter)
 		}
 	}
+	hasFilter := !reflect.DeepEqual(filter, expression.ConditionBuilder{})
+	hasProjection := !reflect.DeepEqual(expr.projection, expression.ProjectionBuilder{})
+	if hasFilter {
+		builder = builder.WithFilter(filter)
+	}
+	if hasProjection {
+		builder = builder.WithProjection(expr.projection)
+	}
 
-	if !reflect.DeepEqual(expr.keyCondition, expression.ConditionBuilder{}) {
-		builder = builder.WithKeyCondition(expr.keyCondition)
-		awsExpressionBuilder, _ := builder.Build()
-
-		input = dynamodb.ScanInput{
-			ExpressionAttributeNames:  awsExpressionBuilder.Names(),
-			ExpressionAttributeValues: awsExpressionBuilder.Values(),
-			FilterExpression:          awsExpressionBuilder.KeyCondition(),
-			TableName:                 aws.String(expr.dynamoDBTable),
+	if hasFilter || hasProjection {
+		awsExpressionBuilder, err := builder.Build()
+		if err != nil {
+			return nil, err
 		}
+
+		input.ExpressionAttributeNames = awsExpressionBuilder.Names()
+		input.ExpressionAttributeValues = awsExpressionBuilder.Values()
+		input.ProjectionExpression = awsExpressionBuilder.Projection()
+		input.FilterExpression = awsExpressionBuilder.Filter()
+	}
+
+	if len(expr.dynamoDBIndex) > 0 {
+		input.IndexName = aws.String(expr.dynamoDBIndex)
 	}
+	input.ConsistentRead = expr.consistentRead
 
 	if expr.limit != nil && *expr.limit >= 1 {
 		input.Limit = expr.limit
 	}
 
+	if expr.segment != nil && expr.totalSegments != nil {
+		input.Segment = expr.segment
+		input.TotalSegments = expr.totalSegments
+	}
+
 	if len(expr.exclusiveStartKey) > 0 {
 		input.ExclusiveStartKey = expr.exclusiveStartKey
 	}
 
+	if len(expr.cursor) > 0 {
+		startKey, err := decodeCursor(expr.cursor, expr.dynamoDBTable, expr.dynamoDBIndex, expr.cursorSecret)
+		if err != nil {
+			return nil, err
+		}
+		input.ExclusiveStartKey = startKey
+	}
+
 	return &input, nil
 }
 
 // BuildGetInput build get input expression
 func (expr *AwsExpressionWrapper) BuildGetInput() (*dynamodb.GetItemInput, error) {
 	if len(expr.dynamoDBTable) < 1 {
-		return nil, errors.New("missing table name")
+		return nil, newValidationErr(tableNameField, "missing table name")
+	}
+	if expr.err != nil {
+		return nil, expr.err
 	}
 
 	keys, err := expr.CreateQueryKeys()
@@ -353,16 +678,30 @@ func (expr *AwsExpressionWrapper) BuildGetInput() (*dynamodb.GetItemInput, error
 		return nil, err
 	}
 
-	return &dynamodb.GetItemInput{
-		TableName: aws.String(expr.dynamoDBTable),
-		Key:       keys,
-	}, nil
+	input := &dynamodb.GetItemInput{
+		TableName:      aws.String(expr.dynamoDBTable),
+		Key:            keys,
+		ConsistentRead: expr.consistentRead,
+	}
+
+	projection, err := expr.buildProjection()
+	if err != nil {
+		return nil, err
+	}
+	if projection != nil {
+		input.ProjectionExpression = projection.Projection()
+		input.ExpressionAttributeNames = projection.Names()
+	}
+	return input, nil
 }
 
 // BuildDeleteInput build delete input
 func (expr *AwsExpressionWrapper) BuildDeleteInput() (*dynamodb.DeleteItemInput, error) {
 	if len(expr.dynamoDBTable) < 1 {
-		return nil, errors.New("missing table name")
+		return nil, newValidationErr(tableNameField, "missing table name")
+	}
+	if expr.err != nil {
+		return nil, expr.err
 	}
 
 	keys, keyErr := expr.CreateQueryKeys()
@@ -395,7 +734,7 @@ func (expr *AwsExpressionWrapper) BuildDeleteInput() (*dynamodb.DeleteItemInput,
 // CreateQueryKeys creates a query keys
 func (expr *AwsExpressionWrapper) CreateQueryKeys() (map[string]*dynamodb.AttributeValue, error) {
 	if len(expr.partitionKeyName) < 1 || expr.partitionKeyValue == nil {
-		return nil, errors.New("missing partition key")
+		return nil, newValidationErr(partitionKeyField, "missing partition key")
 	}
 
 	attributeValues := map[string]*dynamodb.AttributeValue{
@@ -409,8 +748,52 @@ func (expr *AwsExpressionWrapper) CreateQueryKeys() (map[string]*dynamodb.Attrib
 	return attributeValues, nil
 }
 
+// buildCondition builds the condition expression, returns nil if there is no condition defined
+func (expr *AwsExpressionWrapper) buildCondition() (*expression.Expression, error) {
+	if expr == nil {
+		return nil, nil
+	}
+	if expr.err != nil {
+		return nil, expr.err
+	}
+	if reflect.DeepEqual(expr.conditionExpression, expression.ConditionBuilder{}) {
+		return nil, nil
+	}
+
+	awsExpression, err := expression.NewBuilder().
+		WithCondition(expr.conditionExpression).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	return &awsExpression, nil
+}
+
+// buildProjection builds the projection expression, returns nil if there is no projection defined
+func (expr *AwsExpressionWrapper) buildProjection() (*expression.Expression, error) {
+	if reflect.DeepEqual(expr.projection, expression.ProjectionBuilder{}) {
+		return nil, nil
+	}
+
+	awsExpression, err := expression.NewBuilder().
+		WithProjection(expr.projection).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	return &awsExpression, nil
+}
+
+// withErr keeps the first invalid condition error to be returned when building the input
+func (expr *AwsExpressionWrapper) withErr(err error) *AwsExpressionWrapper {
+	if expr.err == nil {
+		expr.err = err
+	}
+	return expr
+}
+
 // createCondition creates the condition builder
-func createCondition(name string, value interface{}, operator Operator) expression.ConditionBuilder {
+func createCondition(name string, value interface{}, operator Operator) (expression.ConditionBuilder, error) {
 	// check if the interface can be cast to FromToDate as the operation will be different
 	switch obj := value.(type) {
 	case FromToDate:
@@ -419,43 +802,177 @@ func createCondition(name string, value interface{}, operator Operator) expressi
 			return expression.Name(name).Between(
 				expression.Value(obj.FromDate),
 				expression.Value(obj.ToDate),
-			)
+			), nil
 		default:
 			// failsafe as the minimum value is going to be 0 for epoch
-			return expression.Name(name).GreaterThanEqual(expression.Value(obj.FromDate))
+			return expression.Name(name).GreaterThanEqual(expression.Value(obj.FromDate)), nil
 		}
 	}
 
+	switch operator {
+	case BEGINSWITH:
+		prefix, err := stringValue(value, operator)
+		return expression.Name(name).BeginsWith(prefix), err
+	case CONTAINS:
+		substr, err := stringValue(value, operator)
+		return expression.Name(name).Contains(substr), err
+	case EXISTS:
+		return expression.Name(name).AttributeExists(), nil
+	case NOTEXISTS:
+		return expression.Name(name).AttributeNotExists(), nil
+	case ATTRTYPE:
+		attrType, err := stringValue(value, operator)
+		return expression.Name(name).AttributeType(expression.DynamoDBAttributeType(attrType)), err
+	case SIZE:
+		size, ok := value.(SizeCondition)
+		if !ok {
+			return expression.ConditionBuilder{}, fmt.Errorf("SIZE expects a SizeCondition value, got %T", value)
+		}
+		switch size.Operator {
+		case EQUAL, NE, LT, LE, GT, GE, BETWEEN, IN:
+			return compareOperand(expression.Name(name).Size(), size.Value, size.Operator)
+		default:
+			return expression.ConditionBuilder{}, fmt.Errorf("SIZE does not support the %v operator", size.Operator)
+		}
+	default:
+		return compareOperand(expression.Name(name), value, operator)
+	}
+}
+
+// compareOperand creates the comparison condition of the operand, either an attribute or its size
+func compareOperand(operand expression.OperandBuilder, value interface{}, operator Operator) (expression.ConditionBuilder, error) {
 	switch operator {
 	case EQUAL:
-		return expression.Name(name).Equal(expression.Value(value))
+		return expression.Equal(operand, expression.Value(value)), nil
+	case NE:
+		return expression.NotEqual(operand, expression.Value(value)), nil
 	case LT:
-		return expression.Name(name).LessThan(expression.Value(value))
+		return expression.LessThan(operand, expression.Value(value)), nil
 	case LE:
-		return expression.Name(name).LessThanEqual(expression.Value(value))
+		return expression.LessThanEqual(operand, expression.Value(value)), nil
 	case GT:
-		return expression.Name(name).GreaterThan(expression.Value(value))
+		return expression.GreaterThan(operand, expression.Value(value)), nil
 	case GE:
-		return expression.Name(name).GreaterThanEqual(expression.Value(value))
+		return expression.GreaterThanEqual(operand, expression.Value(value)), nil
+	case BETWEEN:
+		bounds, ok := value.(Range)
+		if !ok {
+			return expression.ConditionBuilder{}, fmt.Errorf("BETWEEN expects a Range value, got %T", value)
+		}
+		return expression.Between(operand, expression.Value(bounds.From), expression.Value(bounds.To)), nil
+	case IN:
+		values := inValues(value)
+		if len(values) < 1 {
+			return expression.ConditionBuilder{}, errors.New("IN expects at least one value")
+		}
+		return expression.In(operand, values[0], values[1:]...), nil
 	default:
-		return expression.Name(name).Equal(expression.Value(value))
+		return expression.Equal(operand, expression.Value(value)), nil
 	}
 }
 
 // createKeyCondition creates the condition builder
-func createKeyCondition(name string, value interface{}, operator Operator) expression.KeyConditionBuilder {
+func createKeyCondition(name string, value interface{}, operator Operator) (expression.KeyConditionBuilder, error) {
 	switch operator {
 	case EQUAL:
-		return expression.Key(name).Equal(expression.Value(value))
+		return expression.Key(name).Equal(expression.Value(value)), nil
 	case LT:
-		return expression.Key(name).LessThan(expression.Value(value))
+		return expression.Key(name).LessThan(expression.Value(value)), nil
 	case LE:
-		return expression.Key(name).LessThanEqual(expression.Value(value))
+		return expression.Key(name).LessThanEqual(expression.Value(value)), nil
 	case GT:
-		return expression.Key(name).GreaterThan(expression.Value(value))
+		return expression.Key(name).GreaterThan(expression.Value(value)), nil
 	case GE:
-		return expression.Key(name).GreaterThanEqual(expression.Value(value))
+		return expression.Key(name).GreaterThanEqual(expression.Value(value)), nil
+	case BETWEEN:
+		switch bounds := value.(type) {
+		case Range:
+			return expression.Key(name).Between(expression.Value(bounds.From), expression.Value(bounds.To)), nil
+		case FromToDate:
+			return expression.Key(name).Between(expression.Value(bounds.FromDate), expression.Value(bounds.ToDate)), nil
+		default:
+			return expression.KeyConditionBuilder{}, fmt.Errorf("BETWEEN expects a Range value, got %T", value)
+		}
+	case BEGINSWITH:
+		prefix, err := stringValue(value, operator)
+		return expression.Key(name).BeginsWith(prefix), err
+	case NE, IN, CONTAINS, EXISTS, NOTEXISTS, ATTRTYPE, SIZE:
+		return expression.KeyConditionBuilder{}, fmt.Errorf("operator %v is not supported in key conditions", operator)
 	default:
-		return expression.Key(name).Equal(expression.Value(value))
+		return expression.Key(name).Equal(expression.Value(value)), nil
+	}
+}
+
+// stringValue returns the value of string kind as string, as required by the string functions
+func stringValue(value interface{}, operator Operator) (string, error) {
+	val := reflect.ValueOf(value)
+	if val.Kind() != reflect.String {
+		return "", fmt.Errorf("operator %v expects a string value, got %T", operator, value)
+	}
+	return val.String(), nil
+}
+
+// listValue wraps a single value into a list, slices are returned as is
+func listValue(values interface{}) interface{} {
+	val := reflect.ValueOf(values)
+	if val.Kind() == reflect.Slice && val.Type().Elem().Kind() != reflect.Uint8 || val.Kind() == reflect.Array {
+		return values
+	}
+	return []interface{}{values}
+}
+
+// setValue converts a slice of strings, numbers or byte slices to a set attribute value
+// a single byte slice is a binary set of one element, as in listValue
+func setValue(values interface{}) (*dynamodb.AttributeValue, error) {
+	val := reflect.ValueOf(values)
+	if val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8 {
+		val = reflect.ValueOf([]interface{}{values})
+	}
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array || val.Len() == 0 {
+		return nil, fmt.Errorf("set operations expect a non empty slice, got %T", values)
+	}
+
+	set := &dynamodb.AttributeValue{}
+	for idx := 0; idx < val.Len(); idx++ {
+		elem := val.Index(idx)
+		// the elements of []interface{} or pointers are unwrapped to their values
+		for elem.IsValid() && (elem.Kind() == reflect.Interface || elem.Kind() == reflect.Pointer) {
+			elem = elem.Elem()
+		}
+		if !elem.IsValid() {
+			return nil, fmt.Errorf("set element %d is nil", idx)
+		}
+		switch elem.Kind() {
+		case reflect.String:
+			set.SS = append(set.SS, aws.String(elem.String()))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			set.NS = append(set.NS, aws.String(fmt.Sprint(elem.Interface())))
+		case reflect.Slice:
+			if elem.Type().Elem().Kind() != reflect.Uint8 {
+				return nil, fmt.Errorf("unsupported set element %s", elem.Type())
+			}
+			set.BS = append(set.BS, elem.Bytes())
+		default:
+			return nil, fmt.Errorf("unsupported set element %s", elem.Type())
+		}
+	}
+	if (set.SS != nil && (set.NS != nil || set.BS != nil)) || (set.NS != nil && set.BS != nil) {
+		return nil, errors.New("set elements should be of the same type")
+	}
+	return set, nil
+}
+
+// inValues converts the slice or array value to the IN operands, any other value is a single operand
+func inValues(value interface{}) []expression.OperandBuilder {
+	val := reflect.ValueOf(value)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array || val.Type().Elem().Kind() == reflect.Uint8 {
+		return []expression.OperandBuilder{expression.Value(value)}
+	}
+	values := make([]expression.OperandBuilder, 0, val.Len())
+	for idx := 0; idx < val.Len(); idx++ {
+		values = append(values, expression.Value(val.Index(idx).Interface()))
 	}
+	return values
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	dynamoSDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/sghaida/dyorm"
@@ -341,6 +342,31 @@ func Test_BuildExpression(t *testing.T) {
 			t.Errorf("expect building to succeed, got %v", err)
 		}
 	})
+	t.Run("typed keys", func(t *testing.T) {
+		sortKey := dynamodb.DBKeyName("seq")
+		keyNames := dynamodb.DBPSKeyNames{
+			PartitionKey:     "hash",
+			SortKey:          &sortKey,
+			PartitionKeyType: dynamodb.KeyTypeBinary,
+			SortKeyType:      dynamodb.KeyTypeNumber,
+		}
+		seq := dynamodb.DBKeyValue("7")
+		keys := dynamodb.NewDbPSKeyValues(dynamodb.DBKeyValue([]byte{1, 2}), &seq)
+
+		attributes, err := dynamodb.NewExpressionWrapper("request-test").
+			WithKeys(keyNames, keys).
+			CreateQueryKeys()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2}, attributes["hash"].B)
+		assert.Equal(t, "7", aws.StringValue(attributes["seq"].N))
+
+		input, err := dynamodb.NewExpressionWrapper("request-test").
+			WithTypedLastEvaluatedKey(keyNames, keys).
+			BuildScanInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "7", aws.StringValue(input.ExclusiveStartKey["seq"].N))
+		assert.Nil(t, input.ExclusiveStartKey["seq"].S)
+	})
 	t.Run("build scan-input without a table-name", func(t *testing.T) {
 		expr := dynamodb.NewExpressionWrapper("").
 			WithPartitionKey("partKeyName", "partKeyVal").
@@ -352,3 +378,234 @@ func Test_BuildExpression(t *testing.T) {
 	})
 
 }
+
+func Test_BuildConsistentRead(t *testing.T) {
+	query, err := dynamodb.NewExpressionWrapper("table").
+		WithKeyCondition("id", "1", dynamodb.EQUAL).
+		WithConsistentRead(true).
+		BuildQueryInput()
+	assert.NoError(t, err)
+	assert.True(t, aws.BoolValue(query.ConsistentRead))
+
+	scan, err := dynamodb.NewExpressionWrapper("table").WithIndexName("by_date").WithConsistentRead(true).BuildScanInput()
+	assert.NoError(t, err)
+	assert.True(t, aws.BoolValue(scan.ConsistentRead))
+	assert.Equal(t, "by_date", aws.StringValue(scan.IndexName))
+
+	get, err := dynamodb.NewExpressionWrapper("table").WithPartitionKey("id", "1").BuildGetInput()
+	assert.NoError(t, err)
+	assert.Nil(t, get.ConsistentRead)
+}
+
+func Test_BuildProjection(t *testing.T) {
+	t.Run("scan", func(t *testing.T) {
+		scan, err := dynamodb.NewExpressionWrapper("table").WithProjection("name", "email").BuildScanInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "#0, #1", aws.StringValue(scan.ProjectionExpression))
+		assert.Nil(t, scan.FilterExpression)
+		assert.Empty(t, scan.ExpressionAttributeValues)
+	})
+
+	t.Run("scan with filter", func(t *testing.T) {
+		scan, err := dynamodb.NewExpressionWrapper("table").
+			WithCondition("age", 18, dynamodb.GE).
+			WithProjection("name").
+			BuildScanInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "#0 >= :0", aws.StringValue(scan.FilterExpression))
+		assert.Equal(t, "#1", aws.StringValue(scan.ProjectionExpression))
+		assert.Equal(t, "name", aws.StringValue(scan.ExpressionAttributeNames["#1"]))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		get, err := dynamodb.NewExpressionWrapper("table").
+			WithPartitionKey("id", "1").
+			WithProjection("name").
+			BuildGetInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "#0", aws.StringValue(get.ProjectionExpression))
+		assert.Equal(t, "name", aws.StringValue(get.ExpressionAttributeNames["#0"]))
+	})
+}
+
+func Test_BuildExpressionOperators(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    interface{}
+		operator dynamodb.Operator
+		expected string
+		hasError bool
+	}{
+		{name: "not equal", value: 1, operator: dynamodb.NE, expected: "#0 <> :0"},
+		{name: "in", value: []string{"a", "b"}, operator: dynamodb.IN, expected: "#0 IN (:0, :1)"},
+		{name: "in with single value", value: "a", operator: dynamodb.IN, expected: "#0 IN (:0)"},
+		{name: "in without values", value: []string{}, operator: dynamodb.IN, hasError: true},
+		{name: "begins with", value: "USER#", operator: dynamodb.BEGINSWITH, expected: "begins_with (#0, :0)"},
+		{name: "begins with non string", value: 1, operator: dynamodb.BEGINSWITH, hasError: true},
+		{name: "contains", value: "go", operator: dynamodb.CONTAINS, expected: "contains (#0, :0)"},
+		{name: "attribute exists", operator: dynamodb.EXISTS, expected: "attribute_exists (#0)"},
+		{name: "attribute not exists", operator: dynamodb.NOTEXISTS, expected: "attribute_not_exists (#0)"},
+		{name: "attribute type", value: expression.StringSet, operator: dynamodb.ATTRTYPE, expected: "attribute_type (#0, :0)"},
+		{
+			name:     "size",
+			value:    dynamodb.SizeCondition{Operator: dynamodb.GT, Value: 3},
+			operator: dynamodb.SIZE,
+			expected: "size (#0) > :0",
+		},
+		{
+			name:     "size between",
+			value:    dynamodb.SizeCondition{Operator: dynamodb.BETWEEN, Value: dynamodb.Range{From: 1, To: 3}},
+			operator: dynamodb.SIZE,
+			expected: "size (#0) BETWEEN :0 AND :1",
+		},
+		{
+			name:     "size with unsupported operator",
+			value:    dynamodb.SizeCondition{Operator: dynamodb.CONTAINS, Value: 3},
+			operator: dynamodb.SIZE,
+			hasError: true,
+		},
+		{name: "size without size condition", value: 3, operator: dynamodb.SIZE, hasError: true},
+		{name: "generic between", value: dynamodb.Range{From: "a", To: "m"}, operator: dynamodb.BETWEEN, expected: "#0 BETWEEN :0 AND :1"},
+		{name: "between without range", value: 3, operator: dynamodb.BETWEEN, hasError: true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input, err := dynamodb.NewExpressionWrapper("request-test").
+				WithCondition("abc", tc.value, tc.operator).
+				BuildScanInput()
+			if tc.hasError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, aws.StringValue(input.FilterExpression))
+		})
+	}
+
+	t.Run("key conditions", func(t *testing.T) {
+		input, err := dynamodb.NewExpressionWrapper("request-test").
+			WithKeyCondition("partitionID", "123", dynamodb.EQUAL).
+			AndKeyCondition("sortID", "ORDER#", dynamodb.BEGINSWITH).
+			BuildQueryInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "(#0 = :0) AND (begins_with (#1, :1))", aws.StringValue(input.KeyConditionExpression))
+
+		input, err = dynamodb.NewExpressionWrapper("request-test").
+			WithKeyCondition("partitionID", "123", dynamodb.EQUAL).
+			AndKeyCondition("sortID", dynamodb.Range{From: 1, To: 5}, dynamodb.BETWEEN).
+			BuildQueryInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "(#0 = :0) AND (#1 BETWEEN :1 AND :2)", aws.StringValue(input.KeyConditionExpression))
+
+		_, err = dynamodb.NewExpressionWrapper("request-test").
+			WithKeyCondition("partitionID", "123", dynamodb.EQUAL).
+			AndKeyCondition("sortID", "a", dynamodb.CONTAINS).
+			BuildQueryInput()
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid condition is reported on delete", func(t *testing.T) {
+		_, err := dynamodb.NewExpressionWrapper("request-test").
+			WithPartitionKey("partitionID", "1234").
+			AndCondition("abc", 1, dynamodb.EQUAL).
+			OrCondition("abc", 1, dynamodb.BEGINSWITH).
+			BuildDeleteInput()
+		assert.Error(t, err)
+	})
+}
+
+func Test_BuildUpdateExpression(t *testing.T) {
+	t.Run("all update operations", func(t *testing.T) {
+		input, err := dynamodb.NewExpressionWrapper("request-test").
+			WithPartitionKey("partitionID", "1234").
+			WithUpdateField("address.city", "berlin").
+			WithSetIfNotExists("created_at", 1586190435).
+			WithIncrement("views", 1).
+			WithDecrement("stock", 2).
+			WithListAppend("history", []string{"created"}).
+			WithAddToSet("tags", []string{"go", "dynamo"}).
+			WithDeleteFromSet("scores", []int{1, 2}).
+			WithRemove("draft", "items[0]").
+			BuildUpdateInput()
+		assert.NoError(t, err)
+
+		expected := "ADD #0 :0, #1 :1\n" +
+			"DELETE #2 :2\n" +
+			"REMOVE #3, #4[0]\n" +
+			"SET #5.#6 = :3, #7 = if_not_exists(#7, :4), #8 = if_not_exists(#8, :5) - :6, " +
+			"#9 = list_append(if_not_exists(#9, :7), :8)\n"
+		assert.Equal(t, expected, aws.StringValue(input.UpdateExpression))
+		assert.Equal(t, "city", aws.StringValue(input.ExpressionAttributeNames["#6"]))
+		assert.Len(t, input.ExpressionAttributeValues[":1"].SS, 2)
+		assert.Len(t, input.ExpressionAttributeValues[":2"].NS, 2)
+		assert.NotNil(t, input.ExpressionAttributeValues[":7"].L)
+		assert.Len(t, input.ExpressionAttributeValues[":8"].L, 1)
+	})
+
+	t.Run("set of interface values and a single binary", func(t *testing.T) {
+		input, err := dynamodb.NewExpressionWrapper("request-test").
+			WithPartitionKey("partitionID", "1234").
+			WithAddToSet("tags", []interface{}{"go", "dynamo"}).
+			WithDeleteFromSet("hashes", []byte{1, 2}).
+			BuildUpdateInput()
+		assert.NoError(t, err)
+		assert.Equal(t, aws.StringSlice([]string{"go", "dynamo"}), input.ExpressionAttributeValues[":0"].SS)
+		assert.Equal(t, [][]byte{{1, 2}}, input.ExpressionAttributeValues[":1"].BS)
+	})
+
+	t.Run("conditional update with return values", func(t *testing.T) {
+		input, err := dynamodb.NewExpressionWrapper("request-test").
+			WithPartitionKey("partitionID", "1234").
+			WithUpdateField("status", "paid").
+			WithCondition("partitionID", nil, dynamodb.EXISTS).
+			AndCondition("status", "pending", dynamodb.EQUAL).
+			WithReturnValues(dynamodb.ReturnUpdatedOld).
+			BuildUpdateInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "SET #1 = :1\n", aws.StringValue(input.UpdateExpression))
+		assert.Equal(t, "(attribute_exists (#0)) AND (#1 = :0)", aws.StringValue(input.ConditionExpression))
+		assert.Equal(t, "UPDATED_OLD", aws.StringValue(input.ReturnValues))
+	})
+
+	t.Run("update without condition and return values", func(t *testing.T) {
+		input, err := dynamodb.NewExpressionWrapper("request-test").
+			WithPartitionKey("partitionID", "1234").
+			WithUpdateField("status", "paid").
+			BuildUpdateInput()
+		assert.NoError(t, err)
+		assert.Nil(t, input.ConditionExpression)
+		assert.Nil(t, input.ReturnValues)
+	})
+
+	t.Run("list append with a single value", func(t *testing.T) {
+		input, err := dynamodb.NewExpressionWrapper("request-test").
+			WithPartitionKey("partitionID", "1234").
+			WithListAppend("history", "created").
+			BuildUpdateInput()
+		assert.NoError(t, err)
+		assert.Len(t, input.ExpressionAttributeValues[":1"].L, 1)
+	})
+
+	cases := []struct {
+		name   string
+		values interface{}
+	}{
+		{name: "empty set", values: []string{}},
+		{name: "not a slice", values: "go"},
+		{name: "unsupported element", values: []bool{true}},
+		{name: "mixed elements", values: []interface{}{"go", 1}},
+		{name: "nil element", values: []*string{nil}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := dynamodb.NewExpressionWrapper("request-test").
+				WithPartitionKey("partitionID", "1234").
+				WithAddToSet("tags", tc.values).
+				BuildUpdateInput()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -24,9 +24,3 @@ type BaseModel interface {
 	// GetPartSortKey returns the record's partition and sort key
 	GetPartSortKey(name *DynamoTableOrIndexName) DBPSKeyValues
 }
-
-// baseModelsWithErr ..
-type baseModelsWithErr struct {
-	Records []BaseModel
-	Err     error
-}
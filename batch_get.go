@@ -0,0 +1,278 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// maxBatchGetItems the maximum number of keys dynamodb accepts in a single BatchGetItem call
+const maxBatchGetItems = 100
+
+// BatchGetResult the result of BatchGetByIDs
+type BatchGetResult struct {
+	// Records the found records in the order of the requested keys
+	Records []BaseModel
+	// Missing the keys of the records that do not exist, the missing keys are unknown if the returned items
+	// can not be matched with the requested keys eg. when a custom model does not keep the key attributes
+	Missing []DBPSKeyValues
+	// Invalid the keys that were not requested eg. a missing partition or sort key
+	Invalid []InvalidKey
+	// Unprocessed the keys that were not read, either still unprocessed when the retry deadline was reached
+	// or part of a failed call
+	Unprocessed []DBPSKeyValues
+}
+
+// InvalidKey a key that was not requested along with the validation error
+type InvalidKey struct {
+	Keys DBPSKeyValues
+	Err  error
+}
+
+// BatchGetByIDs gets the records by their primary keys from the table of the options, which should be a configured table,
+// the indexes are not supported by batch gets, the table of the model or the main table is used by default.
+// unlike GetByIDs, the missing, invalid and unprocessed keys are reported in the result,
+// the unprocessed keys are retried with exponential backoff and jitter until the BatchWrite retry deadline
+func (h handlerImp) BatchGetByIDs(
+	ctx context.Context, input BaseModel, dbKeys []DBPSKeyValues, options *AwsExpressionWrapper,
+) (BatchGetResult, error) {
+	h = h.route(input, options)
+	if options == nil {
+		options = NewExpressionWrapper(h.config.TableInfo.TableName)
+	}
+	if options.dynamoDBTable == "" {
+		return BatchGetResult{}, newValidationErr(tableNameField, "missing table name")
+	}
+	// the keys are resolved using the key names of the routed table
+	if options.dynamoDBTable != h.config.TableInfo.TableName {
+		return BatchGetResult{}, newValidationErr(tableNameField, fmt.Sprintf("table %s is not configured", options.dynamoDBTable))
+	}
+	if options.dynamoDBIndex != "" {
+		return BatchGetResult{}, newValidationErr("index", "batch gets read the table primary keys, the indexes are not supported")
+	}
+	keyNames := h.config.TableInfo.DBPSKeyNames
+	template, err := batchGetTemplate(keyNames, options)
+	if err != nil {
+		return BatchGetResult{}, err
+	}
+
+	var result BatchGetResult
+	// the keys are requested once, in the order of their first occurrence
+	requested := make([]DBPSKeyValues, 0, len(dbKeys))
+	requests := make([]DBMap, 0, len(dbKeys))
+	seen := make(map[string]bool, len(dbKeys))
+	for _, keys := range dbKeys {
+		attributes, err := batchGetKey(keyNames, keys)
+		if err != nil {
+			result.Invalid = append(result.Invalid, InvalidKey{Keys: keys, Err: err})
+			continue
+		}
+		id := keyID(keyNames, attributes)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		requested = append(requested, keys)
+		requests = append(requests, attributes)
+	}
+
+	items, unprocessed, err := h.getBatches(ctx, options.dynamoDBTable, template, requests)
+
+	found := make(map[string]DBMap, len(items))
+	unmatched := make([]DBMap, 0)
+	for _, item := range items {
+		if id := keyID(keyNames, item); seen[id] {
+			found[id] = item
+			continue
+		}
+		// eg. the key attributes are not part of the returned item
+		unmatched = append(unmatched, item)
+	}
+	pending := make(map[string]bool, len(unprocessed))
+	for _, attributes := range unprocessed {
+		pending[keyID(keyNames, attributes)] = true
+	}
+
+	ordered := make([]DBMap, 0, len(items))
+	for idx, attributes := range requests {
+		id := keyID(keyNames, attributes)
+		switch item, ok := found[id]; {
+		case ok:
+			ordered = append(ordered, item)
+		case pending[id]:
+			result.Unprocessed = append(result.Unprocessed, requested[idx])
+		case len(unmatched) == 0:
+			result.Missing = append(result.Missing, requested[idx])
+		}
+	}
+
+	result.Records = make([]BaseModel, 0, len(items))
+	for _, item := range append(ordered, unmatched...) {
+		mdl, mErr := h.unmarshal(input, item)
+		if mErr != nil {
+			return result, mErr
+		}
+		result.Records = append(result.Records, mdl)
+	}
+	return result, err
+}
+
+// batchGetTemplate creates the read options shared by the batches, the missing key attributes are added to the projection
+// in order to match the returned items with the requested keys
+func batchGetTemplate(keyNames DBPSKeyNames, options *AwsExpressionWrapper) (dynamodb.KeysAndAttributes, error) {
+	template := dynamodb.KeysAndAttributes{ConsistentRead: options.consistentRead}
+	if options.err != nil {
+		return template, options.err
+	}
+	expr, err := options.buildProjection()
+	if err != nil || expr == nil {
+		return template, validationErr("projection", err)
+	}
+
+	// the projected top level attributes, the nested paths eg. #0.#1 are not matched
+	projected := make(map[DBKeyName]bool)
+	for _, path := range strings.Split(aws.StringValue(expr.Projection()), ", ") {
+		if name, ok := expr.Names()[path]; ok {
+			projected[DBKeyName(aws.StringValue(name))] = true
+		}
+	}
+	names := []DBKeyName{keyNames.PartitionKey}
+	if keyNames.SortKey != nil {
+		names = append(names, *keyNames.SortKey)
+	}
+	projection := options.projection
+	for _, name := range names {
+		if !projected[name] {
+			projection = projection.AddNames(expression.Name(string(name)))
+		}
+	}
+	keysExpr, err := expression.NewBuilder().WithProjection(projection).Build()
+	if err != nil {
+		return template, validationErr("projection", err)
+	}
+	template.ProjectionExpression = keysExpr.Projection()
+	template.ExpressionAttributeNames = keysExpr.Names()
+	return template, nil
+}
+
+// batchGetKey creates the key attributes of the record, the sort key is required if the key names have one
+func batchGetKey(keyNames DBPSKeyNames, keys DBPSKeyValues) (DBMap, error) {
+	if keys == nil || len(keys.GetPartitionKey()) < 1 {
+		return nil, newValidationErr(partitionKeyField, "invalid partition key")
+	}
+	if keyNames.SortKey != nil && keys.GetSortKey() == nil {
+		return nil, newValidationErr(sortKeyField, "missing sort key")
+	}
+	attributes, err := NewExpressionWrapper("").WithKeys(keyNames, keys).CreateQueryKeys()
+	if err != nil {
+		return nil, validationErr(partitionKeyField, err)
+	}
+	return attributes, nil
+}
+
+// keyID identifies the record by the values of its key attributes
+func keyID(keyNames DBPSKeyNames, item DBMap) string {
+	id := string(keyValueOf(item[string(keyNames.PartitionKey)]))
+	if keyNames.SortKey != nil {
+		id += "\x00" + string(keyValueOf(item[string(*keyNames.SortKey)]))
+	}
+	return id
+}
+
+// getBatches reads the keys in chunks of 100 using a bounded number of concurrent calls,
+// returns the read items and the keys that were not read, along with the first error if any
+func (h handlerImp) getBatches(
+	ctx context.Context, tableName string, template dynamodb.KeysAndAttributes, keys []DBMap,
+) ([]DBMap, []DBMap, error) {
+	batchCfg := h.config.BatchWrite.withDefaults()
+	deadline := time.Now().Add(batchCfg.RetryDeadline)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	items := make([]DBMap, 0, len(keys))
+	failed := make([]DBMap, 0)
+	sem := make(chan struct{}, batchCfg.MaxConcurrency)
+
+	for page := range Partition(len(keys), maxBatchGetItems) {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chunk []DBMap) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			read, unprocessed, err := h.getBatch(ctx, tableName, template, chunk, batchCfg, deadline)
+
+			mu.Lock()
+			defer mu.Unlock()
+			items = append(items, read...)
+			failed = append(failed, unprocessed...)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(keys[page.Low:page.High])
+	}
+
+	wg.Wait()
+	return items, failed, firstErr
+}
+
+// getBatch reads a single chunk and retries its unprocessed keys until the deadline
+func (h handlerImp) getBatch(
+	ctx context.Context, tableName string, template dynamodb.KeysAndAttributes, chunk []DBMap,
+	batchCfg BatchWriteConfig, deadline time.Time,
+) ([]DBMap, []DBMap, error) {
+	items := make([]DBMap, 0, len(chunk))
+
+	for attempt := 0; ; attempt++ {
+		req := template
+		req.Keys = make([]map[string]*dynamodb.AttributeValue, 0, len(chunk))
+		for _, key := range chunk {
+			req.Keys = append(req.Keys, key)
+		}
+		res, err := h.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]*dynamodb.KeysAndAttributes{
+				tableName: &req,
+			},
+		})
+		if err != nil {
+			return items, chunk, translateErr(err)
+		}
+
+		for _, item := range res.Responses[tableName] {
+			items = append(items, item)
+		}
+		chunk = nil
+		if unprocessed := res.UnprocessedKeys[tableName]; unprocessed != nil {
+			for _, key := range unprocessed.Keys {
+				chunk = append(chunk, key)
+			}
+		}
+		if len(chunk) == 0 {
+			return items, nil, nil
+		}
+
+		wait := backoff(attempt, batchCfg.BaseBackoff, batchCfg.MaxBackoff)
+		if time.Now().Add(wait).After(deadline) {
+			return items, chunk, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return items, chunk, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// unprocessedKeysErr reports the keys that were not read before the retry deadline, it matches ErrThrottled
+func unprocessedKeysErr(keys []DBPSKeyValues) error {
+	return &dbError{kind: ErrThrottled, err: fmt.Errorf("%d keys were not read before the retry deadline", len(keys))}
+}
@@ -0,0 +1,144 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+// batchGetItems creates the stored items of the names, the names are used as partition keys with the sort key "sKey"
+func batchGetItems(names ...string) []map[string]*dynamodb.AttributeValue {
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(names))
+	for _, name := range names {
+		items = append(items, map[string]*dynamodb.AttributeValue{
+			"partKey": {S: aws.String(name)},
+			"sortKey": {S: aws.String("sKey")},
+			"Name":    {S: aws.String(name)},
+		})
+	}
+	return items
+}
+
+// batchGetKeys creates the keys of the names using the sort key "sKey"
+func batchGetKeys(names ...string) []DBPSKeyValues {
+	sKey := DBKeyValue("sKey")
+	keys := make([]DBPSKeyValues, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, NewDbPSKeyValues(DBKeyValue(name), &sKey))
+	}
+	return keys
+}
+
+func TestHandlerImp_BatchGetByIDs(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports the missing and invalid keys in order", func(t *testing.T) {
+		mock := &MockedRetryBatchGet{Items: batchGetItems("a", "b", "c")}
+		repo := handlerImp{config: bulkCfg, DynamoDBAPI: mock}
+
+		keys := append(batchGetKeys("c", "missing", "a", "c"), NewDbPSKeyValues("b", nil), nil)
+		res, err := repo.BatchGetByIDs(ctx, TestBaseModel{}, keys, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []BaseModel{TestBaseModel{Name: "c"}, TestBaseModel{Name: "a"}}, res.Records)
+		assert.Equal(t, []DBPSKeyValues{keys[1]}, res.Missing)
+		assert.Empty(t, res.Unprocessed)
+
+		assert.Len(t, res.Invalid, 2)
+		assert.Equal(t, keys[4], res.Invalid[0].Keys)
+		assert.True(t, errors.Is(res.Invalid[0].Err, ErrValidation))
+		assert.True(t, errors.Is(res.Invalid[1].Err, ErrValidation))
+	})
+
+	t.Run("chunks the keys into 100 items", func(t *testing.T) {
+		names := make([]string, 0, 250)
+		for idx := 0; idx < 250; idx++ {
+			names = append(names, fmt.Sprintf("name-%d", idx))
+		}
+		mock := &MockedRetryBatchGet{Items: batchGetItems(names...)}
+		repo := handlerImp{config: bulkCfg, DynamoDBAPI: mock}
+
+		res, err := repo.BatchGetByIDs(ctx, TestBaseModel{}, batchGetKeys(names...), nil)
+		assert.NoError(t, err)
+		assert.Len(t, res.Records, 250)
+		assert.Equal(t, TestBaseModel{Name: "name-249"}, res.Records[249])
+		assert.Equal(t, 3, mock.Calls)
+		assert.Equal(t, maxBatchGetItems, mock.MaxChunkSize)
+	})
+
+	t.Run("retries the unprocessed keys", func(t *testing.T) {
+		mock := &MockedRetryBatchGet{Items: batchGetItems("a"), UnprocessedCalls: 2}
+		config := bulkCfg
+		config.BatchWrite.RetryDeadline = time.Second
+		repo := handlerImp{config: config, DynamoDBAPI: mock}
+
+		res, err := repo.BatchGetByIDs(ctx, TestBaseModel{}, batchGetKeys("a"), nil)
+		assert.NoError(t, err)
+		assert.Len(t, res.Records, 1)
+		assert.Equal(t, 3, mock.Calls)
+	})
+
+	t.Run("returns the keys that were unprocessed after the deadline", func(t *testing.T) {
+		mock := &MockedRetryBatchGet{Items: batchGetItems("a"), UnprocessedCalls: 1000}
+		repo := handlerImp{config: bulkCfg, DynamoDBAPI: mock}
+
+		keys := batchGetKeys("a")
+		res, err := repo.BatchGetByIDs(ctx, TestBaseModel{}, keys, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, res.Records)
+		assert.Empty(t, res.Missing)
+		assert.Equal(t, keys, res.Unprocessed)
+		assert.True(t, mock.Calls > 1)
+
+		_, err = repo.GetByIDs(ctx, TestBaseModel{}, keys)
+		assert.True(t, errors.Is(err, ErrThrottled))
+	})
+
+	t.Run("uses the table of the options", func(t *testing.T) {
+		config := bulkCfg
+		config.Tables = []DBTableConfig{ordersTable}
+		mock := &MockedRetryBatchGet{Items: []map[string]*dynamodb.AttributeValue{
+			{"order_id": {S: aws.String("1")}, "Name": {S: aws.String("order")}},
+		}}
+		repo := handlerImp{config: config, DynamoDBAPI: mock}
+
+		options := NewExpressionWrapper(ordersTable.TableInfo.TableName)
+		res, err := repo.BatchGetByIDs(ctx, TestBaseModel{}, []DBPSKeyValues{NewDbPSKeyValues("1", nil)}, options)
+		assert.NoError(t, err)
+		assert.Equal(t, []BaseModel{TestBaseModel{Name: "order"}}, res.Records)
+	})
+
+	t.Run("routes the model to its table", func(t *testing.T) {
+		config := bulkCfg
+		config.Tables = []DBTableConfig{ordersTable}
+		mock := &MockedRetryBatchGet{Items: []map[string]*dynamodb.AttributeValue{
+			{"order_id": {S: aws.String("1")}, "Name": {S: aws.String("order")}},
+		}}
+		repo := handlerImp{config: config, DynamoDBAPI: mock}
+
+		res, err := repo.BatchGetByIDs(ctx, orderModel{}, []DBPSKeyValues{NewDbPSKeyValues("1", nil)}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []BaseModel{TestBaseModel{Name: "order"}}, res.Records)
+		assert.Empty(t, res.Invalid)
+	})
+
+	t.Run("with invalid options", func(t *testing.T) {
+		repo := handlerImp{config: bulkCfg, DynamoDBAPI: &MockedRetryBatchGet{}}
+
+		_, err := repo.BatchGetByIDs(ctx, TestBaseModel{}, batchGetKeys("a"), NewExpressionWrapper(""))
+		assert.True(t, errors.Is(err, ErrValidation))
+		// batch gets do not support indexes
+		_, err = repo.BatchGetByIDs(ctx, TestBaseModel{}, batchGetKeys("a"),
+			NewExpressionWrapper(bulkCfg.TableInfo.TableName).WithIndexName("by_name"),
+		)
+		assert.True(t, errors.Is(err, ErrValidation))
+		// the key names of the other tables are unknown
+		_, err = repo.BatchGetByIDs(ctx, TestBaseModel{}, batchGetKeys("a"), NewExpressionWrapper("other"))
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+}
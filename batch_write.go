@@ -0,0 +1,92 @@
+package dynamodb
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// maxBatchWriteItems the maximum number of items dynamodb accepts in a single BatchWriteItem call
+const maxBatchWriteItems = 25
+
+// writeBatches writes the requests in chunks of 25 items using a bounded number of concurrent calls,
+// the unprocessed items are retried with exponential backoff and jitter until the configured deadline.
+// returns the requests that were not written, along with the first error if any
+func (h handlerImp) writeBatches(ctx context.Context, requests []*dynamodb.WriteRequest) ([]*dynamodb.WriteRequest, error) {
+	batchCfg := h.config.BatchWrite.withDefaults()
+	deadline := time.Now().Add(batchCfg.RetryDeadline)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	failed := make([]*dynamodb.WriteRequest, 0)
+	sem := make(chan struct{}, batchCfg.MaxConcurrency)
+
+	for page := range Partition(len(requests), maxBatchWriteItems) {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chunk []*dynamodb.WriteRequest) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			unprocessed, err := h.writeBatch(ctx, chunk, batchCfg, deadline)
+
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, unprocessed...)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(requests[page.Low:page.High])
+	}
+
+	wg.Wait()
+	return failed, firstErr
+}
+
+// writeBatch writes a single chunk and retries its unprocessed items until the deadline
+func (h handlerImp) writeBatch(
+	ctx context.Context, chunk []*dynamodb.WriteRequest, batchCfg BatchWriteConfig, deadline time.Time,
+) ([]*dynamodb.WriteRequest, error) {
+	tableName := h.config.TableInfo.TableName
+
+	for attempt := 0; ; attempt++ {
+		res, err := h.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamodb.WriteRequest{
+				tableName: chunk,
+			},
+		})
+		if err != nil {
+			return chunk, translateErr(err)
+		}
+
+		chunk = res.UnprocessedItems[tableName]
+		if len(chunk) == 0 {
+			return nil, nil
+		}
+
+		wait := backoff(attempt, batchCfg.BaseBackoff, batchCfg.MaxBackoff)
+		if time.Now().Add(wait).After(deadline) {
+			return chunk, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return chunk, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// backoff returns the exponential backoff for the attempt with full jitter
+func backoff(attempt int, base, maxWait time.Duration) time.Duration {
+	wait := maxWait
+	if attempt < 32 && base<<attempt > 0 && base<<attempt < maxWait {
+		wait = base << attempt
+	}
+	return time.Duration(rand.Int63n(int64(wait)) + 1)
+}
@@ -0,0 +1,71 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerImp_writeBatches(t *testing.T) {
+	t.Run("chunks the records into 25 items", func(t *testing.T) {
+		mock := &MockedRetryBatchWrite{}
+		repo := handlerImp{config: bulkCfg, DynamoDBAPI: mock}
+
+		unprocessed, err := repo.BulkAddRecords(context.Background(), TestBaseModel{}, true, generateTestData(60)...)
+		assert.NoError(t, err)
+		assert.Empty(t, unprocessed)
+		assert.Equal(t, 3, mock.Calls)
+		assert.Equal(t, maxBatchWriteItems, mock.MaxChunkSize)
+	})
+
+	t.Run("retries the unprocessed items", func(t *testing.T) {
+		mock := &MockedRetryBatchWrite{UnprocessedCalls: 2}
+		config := bulkCfg
+		config.BatchWrite.RetryDeadline = time.Second
+		repo := handlerImp{config: config, DynamoDBAPI: mock}
+
+		unprocessed, err := repo.BulkUpdateRecords(context.Background(), TestBaseModel{}, generateTestData(10)...)
+		assert.NoError(t, err)
+		assert.Empty(t, unprocessed)
+		assert.Equal(t, 3, mock.Calls)
+	})
+
+	t.Run("returns the items that failed after the deadline", func(t *testing.T) {
+		mock := &MockedRetryBatchWrite{UnprocessedCalls: 1000}
+		repo := handlerImp{config: bulkCfg, DynamoDBAPI: mock}
+
+		unprocessed, err := repo.BulkDeleteRecords(context.Background(),
+			NewDbPSKeyValues("part", func() *DBKeyValue {
+				sKey := DBKeyValue("sKey")
+				return &sKey
+			}()),
+		)
+		assert.NoError(t, err)
+		assert.Len(t, unprocessed, 1)
+		assert.Equal(t, DBKeyValue("part"), unprocessed[0].GetPartitionKey())
+		assert.True(t, mock.Calls > 1)
+	})
+
+	t.Run("stops retrying when the context is canceled", func(t *testing.T) {
+		mock := &MockedRetryBatchWrite{UnprocessedCalls: 1000}
+		config := bulkCfg
+		config.BatchWrite.RetryDeadline = time.Minute
+		repo := handlerImp{config: config, DynamoDBAPI: mock}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		unprocessed, err := repo.BulkAddRecords(ctx, TestBaseModel{}, true, generateTestData(2)...)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Len(t, unprocessed, 2)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 100; attempt++ {
+		wait := backoff(attempt, time.Millisecond, 100*time.Millisecond)
+		assert.True(t, wait > 0)
+		assert.True(t, wait <= 100*time.Millisecond)
+	}
+}
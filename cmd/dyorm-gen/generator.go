@@ -0,0 +1,494 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	dyorm "github.com/sghaida/dyorm"
+)
+
+// genKey a partition or sort key field of the model
+type genKey struct {
+	Field string
+	Attr  string
+	Type  string
+	Param string
+	// KeyType the dyorm key type constant name, empty for string keys
+	KeyType string
+	// Format the strconv call formatting number fields as dynamodbattribute does, empty for strings and bytes
+	Format string
+	// convert the type the number fields are converted to before formatting, empty if the field has that type
+	convert string
+	// integer is set for the fields of an integer underlying type
+	integer bool
+}
+
+// genKeys the partition and sort keys of a table or an index
+type genKeys struct {
+	PartitionKey *genKey
+	SortKey      *genKey
+	// Local is set for the keys of a local secondary index
+	Local bool
+}
+
+// genIndex an index declared with the gsi or lsi tags
+type genIndex struct {
+	Name  string
+	Const string
+	Func  string
+	Keys  genKeys
+}
+
+// genModel the data used to render a single model
+type genModel struct {
+	Name      string
+	ModelType string
+	TypeConst string
+	ConfigFn  string
+	KeysFn    string
+	Table     genKeys
+	Indexes   []genIndex
+	// Version the optimistic locking version field if any
+	Version *genKey
+}
+
+// genFile the data used to render the generated file
+type genFile struct {
+	Package string
+	Strconv bool
+	Models  []genModel
+}
+
+// Generate parses the go source and returns the formatted BaseModel implementation of the provided types,
+// modelNames holds the model type of each type, an empty entry defaults to the type name
+func Generate(fileName string, src []byte, typeNames, modelNames []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fileName, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// the key types are resolved by the underlying type of the fields, as RegisterModel does,
+	// the type errors are ignored since the types declared by the other files of the package are not loaded
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	conf := types.Config{Importer: importer.Default(), Error: func(error) {}}
+	_, _ = conf.Check(file.Name.Name, fset, []*ast.File{file}, info)
+
+	structs := make(map[string]*ast.StructType)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if spec, ok := node.(*ast.TypeSpec); ok {
+			if st, ok := spec.Type.(*ast.StructType); ok && spec.TypeParams == nil {
+				structs[spec.Name.Name] = st
+			}
+		}
+		return true
+	})
+
+	out := genFile{Package: file.Name.Name}
+	for idx, typeName := range typeNames {
+		typeName = strings.TrimSpace(typeName)
+		st, ok := structs[typeName]
+		if !ok {
+			return nil, fmt.Errorf("struct %s not found in %s", typeName, fileName)
+		}
+		modelType := typeName
+		if idx < len(modelNames) && modelNames[idx] != "" {
+			modelType = strings.TrimSpace(modelNames[idx])
+		}
+		model, err := parseModel(typeName, modelType, st, info)
+		if err != nil {
+			return nil, err
+		}
+		out.Strconv = out.Strconv || model.usesStrconv()
+		out.Models = append(out.Models, model)
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, out); err != nil {
+		return nil, err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return code, nil
+}
+
+// parseModel collects the keys declared by the dyorm tags of the struct fields
+func parseModel(typeName, modelType string, st *ast.StructType, info *types.Info) (genModel, error) {
+	model := genModel{
+		Name:      typeName,
+		ModelType: modelType,
+		TypeConst: typeName + "ModelType",
+		ConfigFn:  typeName + "DBConfig",
+		KeysFn:    constructorName(typeName, ""),
+	}
+	indexes := make(map[dyorm.DynamoTableOrIndexName]*genIndex)
+	localIndexes := make(map[dyorm.DynamoTableOrIndexName]bool)
+
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+		tagValue, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return model, err
+		}
+		tag := reflect.StructTag(tagValue)
+		value, ok := tag.Lookup(dyorm.ModelTag)
+		if !ok {
+			continue
+		}
+		keyTags, err := dyorm.ParseModelTag(value)
+		if err != nil {
+			return model, fmt.Errorf("%s.%s: %w", typeName, field.Names[0].Name, err)
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			if _, ok := field.Type.(*ast.StarExpr); ok {
+				return model, fmt.Errorf("%s.%s: pointer keys are not supported", typeName, name.Name)
+			}
+			fieldType := types.ExprString(field.Type)
+			key := &genKey{
+				Field: name.Name,
+				Attr:  dyorm.AttributeName(name.Name, tag),
+				Type:  fieldType,
+				Param: paramName(name.Name),
+			}
+			if err := key.resolve(info.TypeOf(field.Type)); err != nil {
+				return model, fmt.Errorf("%s.%s: %w", typeName, name.Name, err)
+			}
+			for _, keyTag := range keyTags {
+				if keyTag.Version {
+					if err := model.setVersion(key); err != nil {
+						return model, fmt.Errorf("%s.%s: %w", typeName, name.Name, err)
+					}
+					continue
+				}
+				keys := &model.Table
+				if keyTag.Index != "" {
+					index, ok := indexes[keyTag.Index]
+					if !ok {
+						index = &genIndex{
+							Name:  string(keyTag.Index),
+							Const: typeName + "Index" + camelCase(string(keyTag.Index)),
+							Func:  constructorName(typeName, camelCase(string(keyTag.Index))),
+						}
+						indexes[keyTag.Index] = index
+					}
+					localIndexes[keyTag.Index] = localIndexes[keyTag.Index] || keyTag.Local
+					keys = &index.Keys
+				}
+				if err := keys.add(keyTag, key); err != nil {
+					return model, fmt.Errorf("%s.%s: %w", typeName, name.Name, err)
+				}
+			}
+		}
+	}
+
+	if model.Table.PartitionKey == nil {
+		return model, fmt.Errorf("model %s is missing the partition key tag", typeName)
+	}
+	for name, index := range indexes {
+		index.Keys.Local = localIndexes[name]
+		if index.Keys.PartitionKey == nil && index.Keys.Local {
+			index.Keys.PartitionKey = model.Table.PartitionKey
+		}
+		if index.Keys.PartitionKey == nil {
+			return model, fmt.Errorf("index %s is missing the partition key tag", name)
+		}
+		model.Indexes = append(model.Indexes, *index)
+	}
+	sort.Slice(model.Indexes, func(i, j int) bool {
+		return model.Indexes[i].Name < model.Indexes[j].Name
+	})
+	return model, nil
+}
+
+// setVersion sets the version field, which should be an integer
+func (model *genModel) setVersion(key *genKey) error {
+	if model.Version != nil {
+		return errors.New("duplicate version")
+	}
+	if !key.integer {
+		return errors.New("the version should be an integer")
+	}
+	model.Version = key
+	return nil
+}
+
+// resolve sets the key type out of the underlying type of the field eg. KeyTypeNumber for type Cents int64
+func (key *genKey) resolve(typ types.Type) error {
+	if typ == nil || typ == types.Typ[types.Invalid] {
+		return fmt.Errorf("can not resolve the key type %s, declare it in the same file or use its underlying type", key.Type)
+	}
+	switch underlying := typ.Underlying().(type) {
+	case *types.Basic:
+		info := underlying.Info()
+		key.integer = info&types.IsInteger != 0
+		switch {
+		case info&types.IsString != 0:
+			return nil
+		case info&types.IsUnsigned != 0:
+			key.Format, key.convert = "strconv.FormatUint(%s, 10)", "uint64"
+		case info&types.IsInteger != 0:
+			key.Format, key.convert = "strconv.FormatInt(%s, 10)", "int64"
+		case underlying.Kind() == types.Float32:
+			key.Format, key.convert = "strconv.FormatFloat(%s, 'f', -1, 32)", "float64"
+		case underlying.Kind() == types.Float64:
+			key.Format, key.convert = "strconv.FormatFloat(%s, 'f', -1, 64)", "float64"
+		}
+		if key.Format != "" {
+			if key.convert == key.Type {
+				key.convert = ""
+			}
+			key.KeyType = "KeyTypeNumber"
+			return nil
+		}
+	case *types.Slice:
+		if elem, ok := underlying.Elem().Underlying().(*types.Basic); ok && elem.Kind() == types.Byte {
+			key.KeyType = "KeyTypeBinary"
+			return nil
+		}
+	}
+	return fmt.Errorf("key type %s is not supported, use a string, number or []byte type", key.Type)
+}
+
+// add sets the partition or sort key of the table or the index
+func (keys *genKeys) add(keyTag dyorm.ModelKeyTag, key *genKey) error {
+	target := &keys.PartitionKey
+	if keyTag.SortKey {
+		target = &keys.SortKey
+	}
+	if *target != nil {
+		kind := "partition"
+		if keyTag.SortKey {
+			kind = "sort"
+		}
+		if keyTag.Index != "" {
+			return fmt.Errorf("duplicate %s key for index %s", kind, keyTag.Index)
+		}
+		return fmt.Errorf("duplicate %s key", kind)
+	}
+	*target = key
+	return nil
+}
+
+// Params returns the constructor parameters of the keys
+func (keys genKeys) Params() string {
+	params := keys.PartitionKey.Param + " " + keys.PartitionKey.Type
+	if keys.SortKey != nil {
+		params += ", " + keys.SortKey.Param + " " + keys.SortKey.Type
+	}
+	return params
+}
+
+// Args returns the constructor arguments of the keys read from the receiver m
+func (keys genKeys) Args() string {
+	args := "m." + keys.PartitionKey.Field
+	if keys.SortKey != nil {
+		args += ", m." + keys.SortKey.Field
+	}
+	return args
+}
+
+// Value returns the expression converting the constructor parameter to a key value
+func (key genKey) Value() string {
+	if key.Format != "" {
+		value := key.Param
+		if key.convert != "" {
+			value = key.convert + "(" + value + ")"
+		}
+		return "dyorm.DBKeyValue(" + fmt.Sprintf(key.Format, value) + ")"
+	}
+	return "dyorm.DBKeyValue(" + key.Param + ")"
+}
+
+// usesStrconv reports whether the generated code of the model needs the strconv package
+func (model genModel) usesStrconv() bool {
+	all := []genKeys{model.Table}
+	for _, index := range model.Indexes {
+		all = append(all, index.Keys)
+	}
+	for _, keys := range all {
+		if keys.PartitionKey.Format != "" || (keys.SortKey != nil && keys.SortKey.Format != "") {
+			return true
+		}
+	}
+	return false
+}
+
+// constructorName returns the key constructor name, exported only for exported types
+func constructorName(typeName, suffix string) string {
+	if ast.IsExported(typeName) {
+		return "New" + typeName + suffix + "Keys"
+	}
+	return "new" + camelCase(typeName) + suffix + "Keys"
+}
+
+// camelCase converts snake, kebab or dotted names to CamelCase eg. user_by_email to UserByEmail
+func camelCase(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// paramName returns the lower camel case parameter name of the field
+func paramName(fieldName string) string {
+	runes := []rune(fieldName)
+	for idx := 0; idx < len(runes) && unicode.IsUpper(runes[idx]); idx++ {
+		// keep the last upper case letter of an acronym followed by a lower case one eg. URLPath to urlPath
+		if idx > 0 && idx+1 < len(runes) && unicode.IsLower(runes[idx+1]) {
+			break
+		}
+		runes[idx] = unicode.ToLower(runes[idx])
+	}
+	name := string(runes)
+	if token.IsKeyword(name) || name == "m" || name == "fmt" || name == "dyorm" {
+		name += "Key"
+	}
+	return name
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by dyorm-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .Strconv}}
+	"strconv"
+{{end}}
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	dyorm "github.com/sghaida/dyorm"
+)
+{{range .Models}}{{$model := .}}
+var _ dyorm.BaseModel = {{.Name}}{}
+{{- if .Version}}
+var _ dyorm.VersionedModel = {{.Name}}{}
+{{- end}}
+
+const (
+	// {{.TypeConst}} the model type of {{.Name}}
+	{{.TypeConst}} dyorm.DBModelName = {{printf "%q" .ModelType}}
+{{- range .Indexes}}
+	// {{.Const}} the {{.Name}} index name
+	{{.Const}} dyorm.DynamoTableOrIndexName = {{printf "%q" .Name}}
+{{- end}}
+)
+
+// {{.ConfigFn}} returns the DBConfig of {{.Name}} for the provided table
+func {{.ConfigFn}}(tableName string) dyorm.DBConfig {
+	return dyorm.DBConfig{
+		TableInfo: dyorm.DBTableInfo{
+			TableName:    tableName,
+			DBPSKeyNames: dyorm.DBPSKeyNames{{template "keyNames" .Table}},
+		},
+		Indexes: map[dyorm.DynamoTableOrIndexName]dyorm.DBPSKeyNames{
+		{{- range .Indexes}}
+			{{.Const}}: {{template "keyNames" .Keys}},
+		{{- end}}
+		},
+	}
+}
+
+// {{.KeysFn}} creates the table keys of {{.Name}}
+func {{.KeysFn}}({{.Table.Params}}) dyorm.DBPSKeyValues {
+	{{- template "keyValues" .Table}}
+}
+{{range .Indexes}}
+// {{.Func}} creates the {{.Name}} index keys of {{$model.Name}}
+func {{.Func}}({{.Keys.Params}}) dyorm.DBPSKeyValues {
+	{{- template "keyValues" .Keys}}
+}
+{{end}}
+// GetModelType returns the model type of {{.Name}}
+func (m {{.Name}}) GetModelType() dyorm.DBModelName {
+	return {{.TypeConst}}
+}
+
+// Marshal converts {{.Name}} to dynamo map
+func (m {{.Name}}) Marshal() (dyorm.DBMap, error) {
+	return dynamodbattribute.MarshalMap(m)
+}
+
+// Unmarshal converts the dynamo map to {{.Name}}
+func (m {{.Name}}) Unmarshal(dbMap dyorm.DBMap) (dyorm.BaseModel, error) {
+	mdl := {{.Name}}{}
+	err := dynamodbattribute.UnmarshalMap(dbMap, &mdl)
+	return mdl, err
+}
+
+// GetPartSortKey returns the partition and sort key of the table or the provided index
+func (m {{.Name}}) GetPartSortKey(name *dyorm.DynamoTableOrIndexName) dyorm.DBPSKeyValues {
+	if name == nil {
+		return {{.KeysFn}}({{.Table.Args}})
+	}
+{{- if .Indexes}}
+	switch *name {
+	{{- range .Indexes}}
+	case {{.Const}}:
+		return {{.Func}}({{.Keys.Args}})
+	{{- end}}
+	}
+{{- end}}
+	return dyorm.NewDbPSKeyValues("", nil)
+}
+{{- if .Version}}
+
+// GetVersion returns the version attribute name and value of {{.Name}}
+func (m {{.Name}}) GetVersion() (dyorm.FieldName, int64) {
+	return {{printf "%q" .Version.Attr}}, int64(m.{{.Version.Field}})
+}
+{{- end}}
+{{end}}
+{{- define "keyNames"}}{
+	PartitionKey: {{printf "%q" .PartitionKey.Attr}},
+{{- if .PartitionKey.KeyType}}
+	PartitionKeyType: dyorm.{{.PartitionKey.KeyType}},
+{{- end}}
+{{- if .SortKey}}
+	SortKey: func() *dyorm.DBKeyName { name := dyorm.DBKeyName({{printf "%q" .SortKey.Attr}}); return &name }(),
+{{- if .SortKey.KeyType}}
+	SortKeyType: dyorm.{{.SortKey.KeyType}},
+{{- end}}
+{{- end}}
+{{- if .Local}}
+	LocalIndex: true,
+{{- end}}
+}{{end}}
+{{- define "keyValues"}}
+{{- if .SortKey}}
+	sortKey := {{.SortKey.Value}}
+	return dyorm.NewDbPSKeyValues({{.PartitionKey.Value}}, &sortKey)
+{{- else}}
+	return dyorm.NewDbPSKeyValues({{.PartitionKey.Value}}, nil)
+{{- end}}
+{{- end}}
+`))
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const modelsSrc = `package models
+
+import "time"
+
+type User struct {
+	ID        string ` + "`json:\"user_id\" dyorm:\"pk\"`" + `
+	Email     string ` + "`json:\"email_address\" dyorm:\"sk;gsi:user_by_email,pk\"`" + `
+	CreatedAt int64  ` + "`json:\"created_at\" dyorm:\"lsi:user_by_date,sk\"`" + `
+	Name      string ` + "`json:\"name\"`" + `
+}
+
+type order struct {
+	Type    string ` + "`dyorm:\"pk\"`" + `
+	Version int64  ` + "`json:\"version\" dyorm:\"version\"`" + `
+}
+
+type event struct {
+	Hash []byte ` + "`dyorm:\"pk\"`" + `
+	Seq  int64  ` + "`dyorm:\"sk\"`" + `
+}
+
+type invalid struct {
+	ID    string ` + "`dyorm:\"pk\"`" + `
+	Other string ` + "`dyorm:\"pk\"`" + `
+}
+
+type floatVersion struct {
+	ID      string  ` + "`dyorm:\"pk\"`" + `
+	Version float64 ` + "`dyorm:\"version\"`" + `
+}
+
+type pointerKey struct {
+	ID *string ` + "`dyorm:\"pk\"`" + `
+}
+
+type Cents int64
+
+type Digest []byte
+
+type Revision uint32
+
+type payment struct {
+	Amount   Cents    ` + "`dyorm:\"pk\"`" + `
+	Hash     Digest   ` + "`dyorm:\"sk\"`" + `
+	Revision Revision ` + "`dyorm:\"version\"`" + `
+}
+
+type Score float32
+
+type timedKey struct {
+	Created time.Time ` + "`dyorm:\"pk\"`" + `
+}
+
+type floatKey struct {
+	Score Score ` + "`dyorm:\"pk\"`" + `
+}
+
+type remoteKey struct {
+	ID Remote ` + "`dyorm:\"pk\"`" + `
+}
+`
+
+func TestGenerate(t *testing.T) {
+	t.Run("successfully", func(t *testing.T) {
+		code, err := Generate("models.go", []byte(modelsSrc), []string{"User", "order", "event"}, []string{"user", "", ""})
+		assert.NoError(t, err)
+
+		src := string(code)
+		assert.Contains(t, src, "// Code generated by dyorm-gen. DO NOT EDIT.")
+		assert.Contains(t, src, "package models")
+		assert.Contains(t, src, `"strconv"`)
+		assert.Contains(t, src, "sortKey := dyorm.DBKeyValue(strconv.FormatInt(createdAt, 10))")
+		assert.Contains(t, src, `UserModelType dyorm.DBModelName = "user"`)
+		assert.Contains(t, src, `orderModelType dyorm.DBModelName = "order"`)
+		assert.Contains(t, src, `UserIndexUserByEmail dyorm.DynamoTableOrIndexName = "user_by_email"`)
+		assert.Contains(t, src, "func UserDBConfig(tableName string) dyorm.DBConfig")
+		assert.Contains(t, src, "func NewUserKeys(id string, email string) dyorm.DBPSKeyValues")
+		// local indexes share the table partition key
+		assert.Contains(t, src, "func NewUserUserByDateKeys(id string, createdAt int64) dyorm.DBPSKeyValues")
+		assert.Contains(t, src, "LocalIndex:   true")
+		assert.Contains(t, src, "return NewUserUserByEmailKeys(m.Email)")
+		// keywords are not used as parameter names
+		assert.Contains(t, src, "func newOrderKeys(typeKey string) dyorm.DBPSKeyValues")
+		// number and binary keys declare their key types
+		assert.Contains(t, src, "SortKeyType:      dyorm.KeyTypeNumber")
+		assert.Contains(t, src, "PartitionKeyType: dyorm.KeyTypeBinary")
+		assert.Contains(t, src, "dyorm.NewDbPSKeyValues(dyorm.DBKeyValue(hash), &sortKey)")
+		// the version field implements VersionedModel
+		assert.Contains(t, src, "var _ dyorm.VersionedModel = order{}")
+		assert.Contains(t, src, `return "version", int64(m.Version)`)
+		assert.NotContains(t, src, "var _ dyorm.VersionedModel = User{}")
+	})
+
+	t.Run("without non string keys", func(t *testing.T) {
+		code, err := Generate("models.go", []byte(modelsSrc), []string{"order"}, nil)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(code), `"strconv"`)
+	})
+
+	t.Run("named key types", func(t *testing.T) {
+		code, err := Generate("models.go", []byte(modelsSrc), []string{"payment"}, nil)
+		assert.NoError(t, err)
+
+		src := string(code)
+		start := strings.Index(src, "func paymentDBConfig")
+		end := start + strings.Index(src[start:], "\n}\n")
+		assert.Equal(t, `func paymentDBConfig(tableName string) dyorm.DBConfig {
+	return dyorm.DBConfig{
+		TableInfo: dyorm.DBTableInfo{
+			TableName: tableName,
+			DBPSKeyNames: dyorm.DBPSKeyNames{
+				PartitionKey:     "Amount",
+				PartitionKeyType: dyorm.KeyTypeNumber,
+				SortKey:          func() *dyorm.DBKeyName { name := dyorm.DBKeyName("Hash"); return &name }(),
+				SortKeyType:      dyorm.KeyTypeBinary,
+			},
+		},
+		Indexes: map[dyorm.DynamoTableOrIndexName]dyorm.DBPSKeyNames{},
+	}`, src[start:end])
+		assert.Contains(t, src, `	sortKey := dyorm.DBKeyValue(hash)
+	return dyorm.NewDbPSKeyValues(dyorm.DBKeyValue(strconv.FormatInt(int64(amount), 10)), &sortKey)`)
+		assert.Contains(t, src, `return "Revision", int64(m.Revision)`)
+
+		// the numbers are formatted as dynamodbattribute marshals them
+		code, err = Generate("models.go", []byte(modelsSrc), []string{"floatKey"}, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, string(code), "dyorm.DBKeyValue(strconv.FormatFloat(float64(score), 'f', -1, 32))")
+	})
+
+	cases := []struct {
+		name  string
+		types []string
+	}{
+		{name: "missing type", types: []string{"Unknown"}},
+		{name: "duplicate key", types: []string{"invalid"}},
+		{name: "pointer key", types: []string{"pointerKey"}},
+		{name: "non integer version", types: []string{"floatVersion"}},
+		{name: "unresolved key type", types: []string{"remoteKey"}},
+		{name: "unsupported key type", types: []string{"timedKey"}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Generate("models.go", []byte(modelsSrc), tc.types, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParamName(t *testing.T) {
+	assert.Equal(t, "id", paramName("ID"))
+	assert.Equal(t, "urlPath", paramName("URLPath"))
+	assert.Equal(t, "createdAt", paramName("CreatedAt"))
+	assert.Equal(t, "rangeKey", paramName("Range"))
+}
@@ -0,0 +1,75 @@
+// dyorm-gen generates the BaseModel implementation of structs annotated with dyorm struct tags
+//
+// it is meant to be used with go generate, the types should be declared in the file that holds the directive,
+// the key types are resolved by their underlying type eg. type Cents int64 is a number key, so named key types
+// should be declared in the same file or in the standard library, the keys should be strings, numbers or byte slices
+//
+//	//go:generate dyorm-gen -type=User,Order -model=user,order
+//	type User struct {
+//		ID    string `json:"user_id" dyorm:"pk"`
+//		Email string `json:"email_address" dyorm:"sk;gsi:user_by_email,pk"`
+//	}
+//
+// for each type it emits GetModelType, Marshal, Unmarshal and GetPartSortKey, the model type and index name constants,
+// typed key constructors eg. NewUserKeys(id, email) and NewUserUserByEmailKeys(email),
+// and UserDBConfig(tableName) which returns the DBConfig the model keys belong to
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("dyorm-gen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of type names; must be set")
+	modelNames := flag.String("model", "", "comma-separated list of model types matching -type; defaults to the type names")
+	output := flag.String("output", "", "output file name; defaults to <file>_dyorm.go")
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fileName := os.Getenv("GOFILE")
+	if flag.NArg() > 0 {
+		fileName = flag.Arg(0)
+	}
+	if fileName == "" {
+		log.Fatal("missing source file, run it with go generate or pass the file name")
+	}
+
+	types := strings.Split(*typeNames, ",")
+	models := make([]string, len(types))
+	if *modelNames != "" {
+		names := strings.Split(*modelNames, ",")
+		if len(names) != len(types) {
+			log.Fatal("-model should have the same number of entries as -type")
+		}
+		copy(models, names)
+	}
+
+	src, err := os.ReadFile(fileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := Generate(fileName, src, types, models)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outName := *output
+	if outName == "" {
+		outName = strings.TrimSuffix(fileName, filepath.Ext(fileName)) + "_dyorm.go"
+	}
+	if err := os.WriteFile(outName, code, 0o644); err != nil {
+		log.Fatal(fmt.Errorf("writing %s: %w", outName, err))
+	}
+}
@@ -2,18 +2,16 @@ package dynamodb
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"math"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/google/uuid"
 )
 
 func (h handlerImp) AddRecord(ctx context.Context, in BaseModel, createSortKey bool) (DBPSKeyValues, error) {
-	item, keys, err := h.createPutItem(in, true, createSortKey)
+	h = h.route(in, nil)
+	item, keys, err := h.createPutItem(in, true, createSortKey, true)
 	if err != nil {
 		return nil, err
 	}
@@ -27,64 +25,70 @@ func (h handlerImp) AddRecord(ctx context.Context, in BaseModel, createSortKey b
 	// triggering the put operation
 	_, err = h.PutItemWithContext(ctx, &input)
 	if err != nil {
-		return nil, err
+		return nil, translateErr(err)
 	}
 
 	return keys, nil
 }
 
+// UpdateRecordByID replaces the record identified by dbKeys,
+// versioned models are written only if the stored version matches the model version, which is incremented
 func (h handlerImp) UpdateRecordByID(ctx context.Context, in BaseModel, dbKeys DBPSKeyValues) error {
+	h = h.route(in, nil)
 	tabInfo := h.config.TableInfo
 
 	if tabInfo.SortKey != nil && dbKeys.GetSortKey() == nil {
-		return errors.New("missing required sorting key")
+		return newValidationErr(sortKeyField, "missing required sorting key")
 	}
 	// marshaling the input
 	item, err := in.Marshal()
 	if err != nil {
 		return err
 	}
-
-	partitionKey := string(dbKeys.GetPartitionKey())
+	h.setModelType(item, in)
 
 	// defining the partition and sort keys
-	item[string(tabInfo.PartitionKey)] = &dynamodb.AttributeValue{
-		S: aws.String(partitionKey),
-	}
+	item[string(tabInfo.PartitionKey)] = tabInfo.PartitionKeyType.AttributeValue(dbKeys.GetPartitionKey())
 	if tabInfo.SortKey != nil {
-		sortKey := string(*dbKeys.GetSortKey())
-		item[string(*tabInfo.SortKey)] = &dynamodb.AttributeValue{
-			S: aws.String(sortKey),
-		}
+		item[string(*tabInfo.SortKey)] = tabInfo.SortKeyType.AttributeValue(*dbKeys.GetSortKey())
 	}
 	// create the put request
 	input := dynamodb.PutItemInput{
 		Item:      item,
 		TableName: aws.String(tabInfo.TableName),
 	}
+	version := modelVersion(in)
+	if err := version.applyToPut(&input); err != nil {
+		return err
+	}
 	// triggering the put operation
 	_, err = h.PutItemWithContext(ctx, &input)
-	return err
+	return version.conflictErr(err)
 }
 
-// Update a dynamo item attributes
+// Update a dynamo item attributes of the main table, use ForModel to update the records of the other tables
 //  - to update the entire item the [data] map need to be populated with all item fields.
 //  - to update some fields only the fields to be updated need to be provided.
+//  - to apply optimistic locking the version attribute is set to its ExpectedVersion, which is incremented.
 func (h handlerImp) Update(ctx context.Context, partKey string, sortKey *string, data map[FieldName]interface{}) error {
 	tabInfo := h.config.TableInfo
 
 	if tabInfo.SortKey != nil && sortKey == nil {
-		return errors.New("missing required sorting key")
+		return newValidationErr(sortKeyField, "missing required sorting key")
 	}
 
 	builder := NewExpressionWrapper(tabInfo.TableName)
-	builder.WithPartitionKey(string(tabInfo.PartitionKey), partKey)
+	builder.WithTypedPartitionKey(string(tabInfo.PartitionKey), DBKeyValue(partKey), tabInfo.PartitionKeyType)
 
 	if sortKey != nil {
-		builder.WithSortingKey(string(*tabInfo.SortKey), *sortKey)
+		builder.WithTypedSortingKey(string(*tabInfo.SortKey), DBKeyValue(*sortKey), tabInfo.SortKeyType)
 	}
 
 	for k, v := range data {
+		if expected, ok := v.(ExpectedVersion); ok {
+			builder.WithVersion(string(k), int64(expected))
+			continue
+		}
 		builder.WithUpdateField(string(k), v)
 	}
 
@@ -94,44 +98,124 @@ func (h handlerImp) Update(ctx context.Context, partKey string, sortKey *string,
 	}
 
 	_, err = h.UpdateItemWithContext(ctx, updateRequest)
+	return builder.version.conflictErr(err)
+}
+
+// UpdateByID partially updates the record identified by dbKeys using the update expression of the wrapper
+// eg. SET, ADD, REMOVE and DELETE operations, without reading the record first
+// the record is created if it does not exist, unless the wrapper holds a condition eg. EXISTS on the partition key
+func (h handlerImp) UpdateByID(ctx context.Context, dbKeys DBPSKeyValues, update *AwsExpressionWrapper) error {
+	h = h.route(nil, update)
+	_, err := h.updateItem(ctx, dbKeys, update)
 	return err
 }
 
+// UpdateAndReturn partially updates the record identified by dbKeys and unmarshals the returned attributes using input
+// the update returns all the new attributes unless WithReturnValues was set, nil is returned if there are no attributes
+func (h handlerImp) UpdateAndReturn(
+	ctx context.Context, input BaseModel, dbKeys DBPSKeyValues, update *AwsExpressionWrapper,
+) (BaseModel, error) {
+	h = h.route(input, update)
+	if update != nil && len(update.returnValues) == 0 {
+		// the return values are set on a copy as the update may be reused
+		returning := *update
+		update = returning.WithReturnValues(ReturnAllNew)
+	}
+	res, err := h.updateItem(ctx, dbKeys, update)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Attributes) < 1 {
+		return nil, nil
+	}
+	return h.unmarshal(input, res.Attributes)
+}
+
+// updateItem builds and executes the update request of the record identified by dbKeys
+func (h handlerImp) updateItem(
+	ctx context.Context, dbKeys DBPSKeyValues, update *AwsExpressionWrapper,
+) (*dynamodb.UpdateItemOutput, error) {
+	tabInfo := h.config.TableInfo
+	// check for required attributes
+	if dbKeys == nil || len(dbKeys.GetPartitionKey()) < 1 {
+		return nil, newValidationErr(partitionKeyField, "missing required partition key")
+	}
+	if tabInfo.SortKey != nil && dbKeys.GetSortKey() == nil {
+		return nil, newValidationErr(sortKeyField, "missing required sort key")
+	}
+
+	if update == nil {
+		update = NewExpressionWrapper(tabInfo.TableName)
+	}
+
+	req, err := update.
+		WithKeys(tabInfo.DBPSKeyNames, dbKeys).
+		BuildUpdateInput()
+	if err != nil {
+		return nil, err
+	}
+	res, err := h.UpdateItemWithContext(ctx, req)
+	return res, update.version.conflictErr(err)
+}
+
 // DeleteRecordByID deletes a record from dynamo db for the defined dbKeys if the provided filter is matched
 func (h handlerImp) DeleteRecordByID(ctx context.Context, dbKeys DBPSKeyValues, filters *AwsExpressionWrapper) error {
+	h = h.route(nil, filters)
 	tabInfo := h.config.TableInfo
 	// check for required attributes
 	if len(dbKeys.GetPartitionKey()) < 1 {
-		return errors.New("missing required partition key")
+		return newValidationErr(partitionKeyField, "missing required partition key")
 	}
 	if tabInfo.SortKey != nil && dbKeys.GetSortKey() == nil {
-		return errors.New("missing required sort key")
+		return newValidationErr(sortKeyField, "missing required sort key")
 	}
 
 	if filters == nil {
 		filters = NewExpressionWrapper(tabInfo.TableName)
 	}
 
-	filters.WithPartitionKey(string(tabInfo.PartitionKey), string(dbKeys.GetPartitionKey()))
-	if tabInfo.SortKey != nil {
-		filters.WithSortingKey(string(*tabInfo.SortKey), string(*dbKeys.GetSortKey()))
-	}
+	filters.WithKeys(tabInfo.DBPSKeyNames, dbKeys)
 
 	req, err := filters.BuildDeleteInput()
 	if err != nil {
 		return err
 	}
 	_, err = h.DeleteItemWithContext(ctx, req)
-	return err
+	return translateErr(err)
 }
 
 func (h handlerImp) BulkAddRecords(ctx context.Context, baseModel BaseModel, createSortKey bool, records ...BaseModel) ([]BaseModel, error) {
+	h = h.route(baseModel, nil)
 	return h.batchWrite(ctx, baseModel, records, true, createSortKey)
 }
 
 // BulkUpdateRecords updates multiple DynamoDB records
+// versioned records are written one by one using a conditional put, since batch writes do not support conditions,
+// the records with a version conflict are returned along with the other unprocessed records
 func (h handlerImp) BulkUpdateRecords(ctx context.Context, baseModel BaseModel, records ...BaseModel) ([]BaseModel, error) {
-	return h.batchWrite(ctx, baseModel, records, false, false)
+	h = h.route(baseModel, nil)
+	plain := make([]BaseModel, 0, len(records))
+	versioned := make([]BaseModel, 0)
+	for _, rec := range records {
+		if modelVersion(rec) != nil {
+			versioned = append(versioned, rec)
+			continue
+		}
+		plain = append(plain, rec)
+	}
+	if len(versioned) == 0 {
+		return h.batchWrite(ctx, baseModel, records, false, false)
+	}
+
+	failed, err := h.putVersioned(ctx, versioned)
+	if len(plain) > 0 {
+		unprocessed, batchErr := h.batchWrite(ctx, baseModel, plain, false, false)
+		failed = append(failed, unprocessed...)
+		if err == nil {
+			err = batchErr
+		}
+	}
+	return failed, err
 }
 
 // BulkDeleteRecords delete a bulk of dynamo records
@@ -142,17 +226,13 @@ func (h handlerImp) BulkDeleteRecords(ctx context.Context, dbKeys ...DBPSKeyValu
 	items := make([]*dynamodb.WriteRequest, 0, len(dbKeys))
 
 	for _, key := range dbKeys {
-		expr := NewExpressionWrapper(h.config.TableInfo.TableName).
-			WithPartitionKey(string(tableKeys.PartitionKey), string(key.GetPartitionKey()))
-
 		if tableKeys.SortKey != nil && key.GetSortKey() == nil {
-			return dbKeys, errors.New("missing required sort key")
-		}
-		if tableKeys.SortKey != nil && key.GetSortKey() != nil {
-			expr.WithSortingKey(string(*tableKeys.SortKey), string(*key.GetSortKey()))
+			return dbKeys, newValidationErr(sortKeyField, "missing required sort key")
 		}
 
-		attribute, err := expr.CreateQueryKeys()
+		attribute, err := NewExpressionWrapper(h.config.TableInfo.TableName).
+			WithKeys(tableKeys, key).
+			CreateQueryKeys()
 		if err != nil {
 			return dbKeys, err
 		}
@@ -164,43 +244,31 @@ func (h handlerImp) BulkDeleteRecords(ctx context.Context, dbKeys ...DBPSKeyValu
 		}
 		items = append(items, item)
 	}
-	requests := map[string][]*dynamodb.WriteRequest{
-		tabInfo.TableName: items,
-	}
-	input := &dynamodb.BatchWriteItemInput{
-		RequestItems: requests,
-	}
-	out, err := h.BatchWriteItemWithContext(ctx, input)
-	if err != nil {
-		return dbKeys, err
-	}
 
-	unprocessedItems := make([]DBPSKeyValues, 0, len(dbKeys))
-	for _, item := range out.UnprocessedItems[tabInfo.TableName] {
-		dbKey := dbPSKeyValues{}
-		var partKey string
-		var sortKey string
+	failed, err := h.writeBatches(ctx, items)
 
-		_ = dynamodbattribute.Unmarshal(item.DeleteRequest.Key[string(tabInfo.PartitionKey)], &partKey)
-		_ = dynamodbattribute.Unmarshal(item.DeleteRequest.Key[string(*tabInfo.SortKey)], &sortKey)
-
-		dbKey.partitionKey = DBKeyValue(partKey)
-		if sortKey != "" {
-			s := DBKeyValue(sortKey)
-			dbKey.sortKey = &s
+	unprocessedItems := make([]DBPSKeyValues, 0, len(failed))
+	for _, item := range failed {
+		dbKey := dbPSKeyValues{
+			partitionKey: keyValueOf(item.DeleteRequest.Key[string(tabInfo.PartitionKey)]),
+		}
+		if tabInfo.SortKey != nil {
+			if sortKey := keyValueOf(item.DeleteRequest.Key[string(*tabInfo.SortKey)]); sortKey != "" {
+				dbKey.sortKey = &sortKey
+			}
 		}
 
 		unprocessedItems = append(unprocessedItems, dbKey)
 	}
-	return unprocessedItems, nil
+	return unprocessedItems, err
 }
 
 func (h handlerImp) batchWrite(ctx context.Context, baseModel BaseModel, records []BaseModel, createPartKey, createSortKey bool) ([]BaseModel, error) {
-	max := int(math.Min(25, float64(len(records))))
-	requests := make([]*dynamodb.WriteRequest, 0, max)
+	requests := make([]*dynamodb.WriteRequest, 0, len(records))
 
-	for _, rec := range records[:max] {
-		item, _, err := h.createPutItem(rec, createPartKey, createSortKey)
+	for _, rec := range records {
+		// the bulk adds create new records, which start at version 1
+		item, _, err := h.createPutItem(rec, createPartKey, createSortKey, createPartKey)
 		if err != nil {
 			return records, err
 		}
@@ -210,35 +278,29 @@ func (h handlerImp) batchWrite(ctx context.Context, baseModel BaseModel, records
 		requests = append(requests, &req)
 	}
 
-	bInput := dynamodb.BatchWriteItemInput{
-		RequestItems: map[string][]*dynamodb.WriteRequest{
-			h.config.TableInfo.TableName: requests,
-		},
-	}
+	failed, err := h.writeBatches(ctx, requests)
 
-	unprocessedItems := records[max:]
-	res, err := h.BatchWriteItemWithContext(ctx, &bInput)
-	if err != nil {
-		return records, err
-	}
-	for _, item := range res.UnprocessedItems[h.config.TableInfo.TableName] {
-		dynamoItem := item.PutRequest.Item
-		rec, err := baseModel.Unmarshal(dynamoItem)
-		if err != nil {
-			return records, err
+	unprocessedItems := make([]BaseModel, 0, len(failed))
+	for _, item := range failed {
+		rec, mErr := baseModel.Unmarshal(item.PutRequest.Item)
+		if mErr != nil {
+			return records, mErr
 		}
 		unprocessedItems = append(unprocessedItems, rec)
 	}
 
-	return unprocessedItems, nil
+	return unprocessedItems, err
 }
 
-func (h handlerImp) createPutItem(in BaseModel, createPartKey bool, createSortKey bool) (DBMap, DBPSKeyValues, error) {
+// createPutItem marshals the model along with its keys, generating the missing keys if allowed,
+// initVersion sets the version of versioned models to 1 as for new records
+func (h handlerImp) createPutItem(in BaseModel, createPartKey, createSortKey, initVersion bool) (DBMap, DBPSKeyValues, error) {
 	// marshaling the input
 	item, err := in.Marshal()
 	if err != nil {
 		return nil, nil, err
 	}
+	h.setModelType(item, in)
 
 	tabInfo := h.config.TableInfo
 	partitionKey := in.GetPartSortKey(nil).GetPartitionKey()
@@ -246,29 +308,40 @@ func (h handlerImp) createPutItem(in BaseModel, createPartKey bool, createSortKe
 	sortKey := in.GetPartSortKey(nil).GetSortKey()
 
 	if partitionKey == "" && !createPartKey {
-		return nil, nil, errors.New("missing required partition key")
+		return nil, nil, newValidationErr(partitionKeyField, "missing required partition key")
 	}
 
 	if partitionKey == "" {
+		// generated keys are uuids, which can only be stored in string keys
+		if !isStringKey(tabInfo.PartitionKeyType) {
+			return nil, nil, newValidationErr(
+				partitionKeyField, "missing required partition key, it can not be generated for non string keys",
+			)
+		}
 		partitionKey = DBKeyValue(uuid.New().String())
 	}
 
 	// defining the partition and sort keys
-	item[string(tabInfo.PartitionKey)] = &dynamodb.AttributeValue{
-		S: aws.String(string(partitionKey)),
-	}
+	item[string(tabInfo.PartitionKey)] = tabInfo.PartitionKeyType.AttributeValue(partitionKey)
 	if tabInfo.SortKey != nil && sortKey == nil && !createSortKey {
-		return nil, nil, errors.New("missing required sorting key")
+		return nil, nil, newValidationErr(sortKeyField, "missing required sorting key")
 	}
 
 	if tabInfo.SortKey != nil && sortKey == nil {
+		if !isStringKey(tabInfo.SortKeyType) {
+			return nil, nil, newValidationErr(
+				sortKeyField, "missing required sorting key, it can not be generated for non string keys",
+			)
+		}
 		key := DBKeyValue(uuid.New().String())
 		sortKey = &key
 	}
 	if tabInfo.SortKey != nil && sortKey != nil {
-		item[string(*tabInfo.SortKey)] = &dynamodb.AttributeValue{
-			S: aws.String(string(*sortKey)),
-		}
+		item[string(*tabInfo.SortKey)] = tabInfo.SortKeyType.AttributeValue(*sortKey)
+	}
+	// new records start at version 1
+	if version := modelVersion(in); version != nil && initVersion {
+		item[string(version.name)] = versionValue(1)
 	}
 	keys := dbPSKeyValues{
 		partitionKey: partitionKey,
@@ -276,3 +349,8 @@ func (h handlerImp) createPutItem(in BaseModel, createPartKey bool, createSortKe
 	}
 	return item, keys, nil
 }
+
+// isStringKey checks if the key type is string, the empty type defaults to string
+func isStringKey(keyType DBKeyType) bool {
+	return keyType == "" || keyType == KeyTypeString
+}
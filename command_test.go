@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
@@ -29,6 +30,17 @@ type method int
 const bulkAdd = method(1)
 const bulkUpdate = method(2)
 
+// bulkCfg limits retrying the unprocessed items to keep the tests fast
+var bulkCfg = func() DBConfig {
+	config := cfg
+	config.BatchWrite = BatchWriteConfig{
+		RetryDeadline: 20 * time.Millisecond,
+		BaseBackoff:   time.Millisecond,
+		MaxBackoff:    5 * time.Millisecond,
+	}
+	return config
+}()
+
 func TestHandlerImp_AddRecord(t *testing.T) {
 	cases := []struct {
 		name          string
@@ -101,6 +113,28 @@ func TestHandlerImp_AddRecord(t *testing.T) {
 	}
 }
 
+func TestHandlerImp_createPutItemWithTypedKeys(t *testing.T) {
+	config := cfg
+	config.TableInfo.PartitionKeyType = KeyTypeNumber
+	config.TableInfo.SortKeyType = KeyTypeBinary
+	repo := handlerImp{config: config}
+
+	t.Run("successfully", func(t *testing.T) {
+		item, keys, err := repo.createPutItem(TestBaseModel{Name: "42", SKey: "\x01\x02"}, false, false, false)
+		assert.NoError(t, err)
+		assert.Equal(t, DBKeyValue("42"), keys.GetPartitionKey())
+		assert.Equal(t, "42", aws.StringValue(item[string(pKey)].N))
+		assert.Equal(t, []byte{1, 2}, item[string(sKey)].B)
+	})
+
+	t.Run("keys can not be generated", func(t *testing.T) {
+		_, _, err := repo.createPutItem(TestBaseModel{Age: 12}, true, true, true)
+		assert.Error(t, err)
+		_, _, err = repo.createPutItem(TestBaseModel{Name: "42"}, true, true, true)
+		assert.Error(t, err)
+	})
+}
+
 func TestHandlerImp_UpdateRecordByID(t *testing.T) {
 	validDBKeys := dbPSKeyValues{
 		partitionKey: "part",
@@ -243,6 +277,118 @@ func TestHandlerImp_Update(t *testing.T) {
 	}
 }
 
+func TestHandlerImp_UpdateByID(t *testing.T) {
+	sortKey := DBKeyValue("sort")
+	cases := []struct {
+		name     string
+		dbKeys   DBPSKeyValues
+		update   *AwsExpressionWrapper
+		dbError  error
+		hasError bool
+	}{
+		{
+			name:   "successfully",
+			dbKeys: NewDbPSKeyValues("part", &sortKey),
+			update: NewExpressionWrapper(cfg.TableInfo.TableName).
+				WithIncrement("views", 1).
+				WithRemove("draft"),
+		},
+		{
+			name:     "missing partition key",
+			dbKeys:   NewDbPSKeyValues("", &sortKey),
+			update:   NewExpressionWrapper(cfg.TableInfo.TableName).WithIncrement("views", 1),
+			hasError: true,
+		},
+		{
+			name:     "missing sort key",
+			dbKeys:   NewDbPSKeyValues("part", nil),
+			update:   NewExpressionWrapper(cfg.TableInfo.TableName).WithIncrement("views", 1),
+			hasError: true,
+		},
+		{
+			name:     "without update",
+			dbKeys:   NewDbPSKeyValues("part", &sortKey),
+			hasError: true,
+		},
+		{
+			name:     "with db error",
+			dbKeys:   NewDbPSKeyValues("part", &sortKey),
+			update:   NewExpressionWrapper(cfg.TableInfo.TableName).WithIncrement("views", 1),
+			dbError:  errors.New("fake error"),
+			hasError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			repo := handlerImp{
+				config: cfg,
+				DynamoDBAPI: MockedUpdateItem{
+					Err: tc.dbError,
+				},
+			}
+			err := repo.UpdateByID(context.Background(), tc.dbKeys, tc.update)
+			assert.True(t, tc.hasError == (err != nil), fmt.Sprintf("%v", err))
+		})
+	}
+}
+
+func TestHandlerImp_UpdateAndReturn(t *testing.T) {
+	ctx := context.Background()
+	sortKey := DBKeyValue("sort")
+	dbKeys := NewDbPSKeyValues("part", &sortKey)
+
+	t.Run("successfully", func(t *testing.T) {
+		repo := handlerImp{
+			config: cfg,
+			DynamoDBAPI: MockedUpdateItem{
+				Resp: dynamodb.UpdateItemOutput{
+					Attributes: DBMap{
+						"Name": {S: aws.String("golang")},
+						"Age":  {N: aws.String("13")},
+					},
+				},
+			},
+		}
+		update := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithIncrement("Age", 1).
+			WithCondition(string(pKey), nil, EXISTS)
+
+		res, err := repo.UpdateAndReturn(ctx, TestBaseModel{}, dbKeys, update)
+		assert.NoError(t, err)
+		assert.Equal(t, TestBaseModel{Name: "golang", Age: 13}, res)
+		// all new attributes are returned by default, without changing the caller update
+		assert.Empty(t, update.returnValues)
+	})
+
+	t.Run("without returned attributes", func(t *testing.T) {
+		repo := handlerImp{
+			config:      cfg,
+			DynamoDBAPI: MockedUpdateItem{},
+		}
+		update := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithIncrement("Age", 1).
+			WithReturnValues(ReturnNone)
+
+		res, err := repo.UpdateAndReturn(ctx, TestBaseModel{}, dbKeys, update)
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("with db error", func(t *testing.T) {
+		repo := handlerImp{
+			config:      cfg,
+			DynamoDBAPI: MockedUpdateItem{Err: errors.New("conditional check failed")},
+		}
+		update := NewExpressionWrapper(cfg.TableInfo.TableName).WithIncrement("Age", 1)
+
+		res, err := repo.UpdateAndReturn(ctx, TestBaseModel{}, dbKeys, update)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
 func TestHandlerImp_DeleteRecordByID(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -349,7 +495,7 @@ func TestHandlerImp_BulkAddRecords(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := handlerImp{
-				config: cfg,
+				config: bulkCfg,
 				DynamoDBAPI: MockedBatchWrite{
 					Resp: tc.dbResp,
 					Err:  tc.dbError,
@@ -369,7 +515,7 @@ func TestHandlerImp_BulkUpdateRecords(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := handlerImp{
-				config: cfg,
+				config: bulkCfg,
 				DynamoDBAPI: MockedBatchWrite{
 					Resp: tc.dbResp,
 					Err:  tc.dbError,
@@ -481,7 +627,7 @@ func TestHandlerImp_BulkDeleteRecords(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := handlerImp{
-				config: cfg,
+				config: bulkCfg,
 				DynamoDBAPI: MockedBatchWrite{
 					Resp: tc.dbResp,
 					Err:  tc.dbError,
@@ -498,10 +644,9 @@ func TestHandlerImp_BulkDeleteRecords(t *testing.T) {
 func getBulkWriteTestData(met method) []bulkWriteTestData {
 	cases := []bulkWriteTestData{
 		{
-			name:                 "successfully",
-			in:                   generateTestData(29),
-			dbResp:               dynamodb.BatchWriteItemOutput{},
-			unprocessedItemCount: 4,
+			name:   "successfully",
+			in:     generateTestData(29),
+			dbResp: dynamodb.BatchWriteItemOutput{},
 		},
 		{
 			name: "with unprocessed items",
@@ -527,7 +672,8 @@ func getBulkWriteTestData(met method) []bulkWriteTestData {
 					},
 				},
 			},
-			unprocessedItemCount: 5,
+			// the mock reports the same unprocessed item for each of the 2 chunks on every retry
+			unprocessedItemCount: 2,
 		},
 		{
 			name:                 "with db error",
@@ -0,0 +1,226 @@
+package dynamodb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeySegment a segment of a composite key, either a constant prefix eg. ORDER or the value of a model field
+type KeySegment struct {
+	name     string
+	constant bool
+}
+
+// Prefix creates a constant segment eg. Prefix("USER") in USER#123
+func Prefix(value string) KeySegment {
+	return KeySegment{name: value, constant: true}
+}
+
+// Field creates a segment holding the value of the named field eg. Field("id") in USER#123
+func Field(name string) KeySegment {
+	return KeySegment{name: name}
+}
+
+// KeyTemplate declares a composite key made of segments joined by the delimiter,
+// its errors are ValidationError of the invalid key field, or of key for the template mismatches
+//
+//	orderKey := NewKeyTemplate("#", Prefix("ORDER"), Field("date"), Field("id"))
+//	key, err := orderKey.Build("2024-01-01", 456) // ORDER#2024-01-01#456
+type KeyTemplate struct {
+	delimiter string
+	segments  []KeySegment
+}
+
+// NewKeyTemplate creates the key template of the segments joined by the delimiter
+func NewKeyTemplate(delimiter string, segments ...KeySegment) KeyTemplate {
+	return KeyTemplate{delimiter: delimiter, segments: segments}
+}
+
+// Fields returns the names of the field segments in order
+func (t KeyTemplate) Fields() []string {
+	fields := make([]string, 0, len(t.segments))
+	for _, segment := range t.segments {
+		if !segment.constant {
+			fields = append(fields, segment.name)
+		}
+	}
+	return fields
+}
+
+// Build builds the key out of the values of the field segments, in their order
+func (t KeyTemplate) Build(values ...interface{}) (DBKeyValue, error) {
+	if fields := t.Fields(); len(values) != len(fields) {
+		return "", newValidationErr("key", fmt.Sprintf(
+			"key template expects %d values (%s), got %d", len(fields), strings.Join(fields, ", "), len(values),
+		))
+	}
+	return t.Prefix(len(t.segments), values...)
+}
+
+// Prefix builds the first depth segments of the key, followed by the delimiter if the key has more segments
+// eg. ORDER#2024-01-01# at depth 2, which matches the keys of the date but not of 2024-01-011
+// the values are the values of the field segments within the depth
+func (t KeyTemplate) Prefix(depth int, values ...interface{}) (DBKeyValue, error) {
+	if t.delimiter == "" {
+		return "", newValidationErr("key", "missing key delimiter")
+	}
+	if depth < 1 || depth > len(t.segments) {
+		return "", newValidationErr("key", fmt.Sprintf("key depth %d is out of range [1, %d]", depth, len(t.segments)))
+	}
+
+	parts := make([]string, 0, depth)
+	for _, segment := range t.segments[:depth] {
+		if segment.constant {
+			parts = append(parts, segment.name)
+			continue
+		}
+		if len(values) == 0 {
+			return "", newValidationErr(segment.name, fmt.Sprintf("missing value of key field %s", segment.name))
+		}
+		value := fmt.Sprint(values[0])
+		values = values[1:]
+		if value == "" || strings.Contains(value, t.delimiter) {
+			return "", newValidationErr(segment.name, fmt.Sprintf("invalid value %q of key field %s", value, segment.name))
+		}
+		parts = append(parts, value)
+	}
+	if len(values) > 0 {
+		return "", newValidationErr("key", fmt.Sprintf("too many values for key depth %d", depth))
+	}
+
+	key := strings.Join(parts, t.delimiter)
+	if depth < len(t.segments) {
+		key += t.delimiter
+	}
+	return DBKeyValue(key), nil
+}
+
+// Parse parses the key into the values of the field segments keyed by the field names
+func (t KeyTemplate) Parse(key DBKeyValue) (map[string]string, error) {
+	if t.delimiter == "" {
+		return nil, newValidationErr("key", "missing key delimiter")
+	}
+	parts := strings.Split(string(key), t.delimiter)
+	if len(parts) != len(t.segments) {
+		return nil, newValidationErr("key", fmt.Sprintf("key %s does not match the key template", key))
+	}
+
+	values := make(map[string]string, len(parts))
+	for idx, segment := range t.segments {
+		if segment.constant {
+			if parts[idx] != segment.name {
+				return nil, newValidationErr("key", fmt.Sprintf("key %s does not match the key prefix %s", key, segment.name))
+			}
+			continue
+		}
+		values[segment.name] = parts[idx]
+	}
+	return values, nil
+}
+
+// CompositeKeys declares the key templates of a model's partition and (optional) sort keys
+//
+//	var orderKeys = CompositeKeys{
+//		PartitionKey: NewKeyTemplate("#", Prefix("USER"), Field("user_id")),
+//		SortKey:      NewKeyTemplate("#", Prefix("ORDER"), Field("date"), Field("id")),
+//	}
+type CompositeKeys struct {
+	PartitionKey KeyTemplate
+	SortKey      KeyTemplate
+}
+
+// hasSortKey checks if the sort key template is declared
+func (k CompositeKeys) hasSortKey() bool {
+	return len(k.SortKey.segments) > 0
+}
+
+// Build builds the keys out of the values of the partition key fields followed by the values of the sort key fields
+// eg. orderKeys.Build(userID, date, orderID)
+func (k CompositeKeys) Build(values ...interface{}) (DBPSKeyValues, error) {
+	partitionFields := len(k.PartitionKey.Fields())
+	if len(values) < partitionFields {
+		return nil, newValidationErr(partitionKeyField, fmt.Sprintf("missing partition key values, expected %d", partitionFields))
+	}
+	partitionKey, err := k.PartitionKey.Build(values[:partitionFields]...)
+	if err != nil {
+		return nil, err
+	}
+	if !k.hasSortKey() {
+		if len(values) > partitionFields {
+			return nil, newValidationErr(sortKeyField, "the keys have no sort key template")
+		}
+		return NewDbPSKeyValues(partitionKey, nil), nil
+	}
+
+	sortKey, err := k.SortKey.Build(values[partitionFields:]...)
+	if err != nil {
+		return nil, err
+	}
+	return NewDbPSKeyValues(partitionKey, &sortKey), nil
+}
+
+// Parse parses the keys into the values of the partition and sort key fields keyed by the field names
+func (k CompositeKeys) Parse(keys DBPSKeyValues) (map[string]string, error) {
+	if keys == nil {
+		return nil, newValidationErr(partitionKeyField, "missing keys")
+	}
+	values, err := k.PartitionKey.Parse(keys.GetPartitionKey())
+	if err != nil {
+		return nil, err
+	}
+	if !k.hasSortKey() {
+		return values, nil
+	}
+	if keys.GetSortKey() == nil {
+		return nil, newValidationErr(sortKeyField, "missing sort key")
+	}
+	sortValues, err := k.SortKey.Parse(*keys.GetSortKey())
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range sortValues {
+		values[name] = value
+	}
+	return values, nil
+}
+
+// ParseItem parses the keys of the record eg. within Unmarshal, using the key names of the table
+func (k CompositeKeys) ParseItem(item DBMap, keyNames DBPSKeyNames) (map[string]string, error) {
+	keys := dbPSKeyValues{partitionKey: keyValueOf(item[string(keyNames.PartitionKey)])}
+	if keyNames.SortKey != nil {
+		if value, ok := item[string(*keyNames.SortKey)]; ok {
+			sortKey := keyValueOf(value)
+			keys.sortKey = &sortKey
+		}
+	}
+	return k.Parse(keys)
+}
+
+// WithKeyPrefix sets the key condition matching the partition key and the sort keys starting with
+// the first depth segments of the sort key template, the values are the values of the partition key fields
+// followed by the values of the sort key fields within the depth
+//
+//	// the orders of the user in 2024-01-01: pk = USER#123 AND begins_with(sk, ORDER#2024-01-01#)
+//	filters := NewExpressionWrapper("app").WithKeyPrefix(keyNames, orderKeys, 2, "123", "2024-01-01")
+func (expr *AwsExpressionWrapper) WithKeyPrefix(
+	keyNames DBPSKeyNames, keys CompositeKeys, depth int, values ...interface{},
+) *AwsExpressionWrapper {
+	if keyNames.SortKey == nil || !keys.hasSortKey() {
+		return expr.withErr(newValidationErr(sortKeyField, "key prefix requires a sort key"))
+	}
+	partitionFields := len(keys.PartitionKey.Fields())
+	if len(values) < partitionFields {
+		return expr.withErr(newValidationErr(partitionKeyField, fmt.Sprintf("missing partition key values, expected %d", partitionFields)))
+	}
+	partitionKey, err := keys.PartitionKey.Build(values[:partitionFields]...)
+	if err != nil {
+		return expr.withErr(err)
+	}
+	prefix, err := keys.SortKey.Prefix(depth, values[partitionFields:]...)
+	if err != nil {
+		return expr.withErr(err)
+	}
+	return expr.
+		WithKeyCondition(string(keyNames.PartitionKey), partitionKey, EQUAL).
+		AndKeyCondition(string(*keyNames.SortKey), prefix, BEGINSWITH)
+}
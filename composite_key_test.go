@@ -0,0 +1,128 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+var orderKeys = CompositeKeys{
+	PartitionKey: NewKeyTemplate("#", Prefix("USER"), Field("user_id")),
+	SortKey:      NewKeyTemplate("#", Prefix("ORDER"), Field("date"), Field("id")),
+}
+
+func TestKeyTemplate(t *testing.T) {
+	template := orderKeys.SortKey
+
+	t.Run("build", func(t *testing.T) {
+		key, err := template.Build("2024-01-01", 456)
+		assert.NoError(t, err)
+		assert.Equal(t, DBKeyValue("ORDER#2024-01-01#456"), key)
+		assert.Equal(t, []string{"date", "id"}, template.Fields())
+
+		_, err = template.Build("2024-01-01")
+		assert.True(t, errors.Is(err, ErrValidation))
+		_, err = template.Build("2024#01", 456)
+		var valErr *ValidationError
+		assert.True(t, errors.As(err, &valErr))
+		assert.Equal(t, "date", valErr.Field)
+		_, err = template.Build("", 456)
+		assert.True(t, errors.Is(err, ErrValidation))
+		_, err = NewKeyTemplate("", Field("id")).Build(1)
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		cases := []struct {
+			depth    int
+			values   []interface{}
+			expected DBKeyValue
+			hasError bool
+		}{
+			{depth: 1, expected: "ORDER#"},
+			{depth: 2, values: []interface{}{"2024-01-01"}, expected: "ORDER#2024-01-01#"},
+			{depth: 3, values: []interface{}{"2024-01-01", 456}, expected: "ORDER#2024-01-01#456"},
+			{depth: 2, hasError: true},
+			{depth: 1, values: []interface{}{"2024-01-01"}, hasError: true},
+			{depth: 0, hasError: true},
+			{depth: 4, hasError: true},
+		}
+		for _, tc := range cases {
+			prefix, err := template.Prefix(tc.depth, tc.values...)
+			assert.Equal(t, tc.hasError, errors.Is(err, ErrValidation), err)
+			assert.Equal(t, tc.expected, prefix)
+		}
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		values, err := template.Parse("ORDER#2024-01-01#456")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"date": "2024-01-01", "id": "456"}, values)
+
+		_, err = template.Parse("USER#2024-01-01#456")
+		assert.True(t, errors.Is(err, ErrValidation))
+		_, err = template.Parse("ORDER#2024-01-01")
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+}
+
+func TestCompositeKeys(t *testing.T) {
+	keys, err := orderKeys.Build("123", "2024-01-01", 456)
+	assert.NoError(t, err)
+	assert.Equal(t, DBKeyValue("USER#123"), keys.GetPartitionKey())
+	assert.Equal(t, DBKeyValue("ORDER#2024-01-01#456"), *keys.GetSortKey())
+
+	values, err := orderKeys.Parse(keys)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"user_id": "123", "date": "2024-01-01", "id": "456"}, values)
+
+	item := DBMap{
+		"partKey": {S: aws.String("USER#123")},
+		"sortKey": {S: aws.String("ORDER#2024-01-01#456")},
+	}
+	values, err = orderKeys.ParseItem(item, cfg.TableInfo.DBPSKeyNames)
+	assert.NoError(t, err)
+	assert.Equal(t, "456", values["id"])
+
+	t.Run("with invalid keys", func(t *testing.T) {
+		_, err := orderKeys.Build("123", "2024-01-01")
+		assert.True(t, errors.Is(err, ErrValidation))
+		_, err = orderKeys.Build()
+		assert.True(t, errors.Is(err, ErrValidation))
+
+		userKeys := CompositeKeys{PartitionKey: orderKeys.PartitionKey}
+		_, err = userKeys.Build("123", "extra")
+		assert.True(t, errors.Is(err, ErrValidation))
+		keys, err := userKeys.Build("123")
+		assert.NoError(t, err)
+		assert.Nil(t, keys.GetSortKey())
+
+		_, err = orderKeys.Parse(NewDbPSKeyValues("USER#123", nil))
+		assert.True(t, errors.Is(err, ErrValidation))
+		_, err = orderKeys.ParseItem(DBMap{"partKey": {S: aws.String("ORDER#1")}}, cfg.TableInfo.DBPSKeyNames)
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+}
+
+func TestAwsExpressionWrapper_WithKeyPrefix(t *testing.T) {
+	keyNames := cfg.TableInfo.DBPSKeyNames
+
+	query, err := NewExpressionWrapper("table").WithKeyPrefix(keyNames, orderKeys, 2, "123", "2024-01-01").BuildQueryInput()
+	assert.NoError(t, err)
+	assert.Equal(t, "(#0 = :0) AND (begins_with (#1, :1))", aws.StringValue(query.KeyConditionExpression))
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String("USER#123")}, query.ExpressionAttributeValues[":0"])
+	assert.Equal(t, &dynamodb.AttributeValue{S: aws.String("ORDER#2024-01-01#")}, query.ExpressionAttributeValues[":1"])
+
+	cases := map[string]*AwsExpressionWrapper{
+		"missing partition values": NewExpressionWrapper("table").WithKeyPrefix(keyNames, orderKeys, 1),
+		"out of range depth":       NewExpressionWrapper("table").WithKeyPrefix(keyNames, orderKeys, 5, "123"),
+		"without sort key":         NewExpressionWrapper("table").WithKeyPrefix(DBPSKeyNames{PartitionKey: "pk"}, orderKeys, 1, "123"),
+	}
+	for name, expr := range cases {
+		_, err := expr.BuildQueryInput()
+		assert.True(t, errors.Is(err, ErrValidation), name)
+	}
+}
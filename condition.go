@@ -0,0 +1,89 @@
+package dynamodb
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// Condition a composable condition created using Cond, which can be nested with AND, OR and NOT
+// and passed to WithConditionGroup, AndConditionGroup or OrConditionGroup
+type Condition struct {
+	builder expression.ConditionBuilder
+	err     error
+}
+
+// Cond the entry point of the condition DSL, every group is wrapped in parentheses
+//
+//	// (a = 1 OR b = 2) AND NOT c = 3
+//	Cond.And(
+//		Cond.Or(Cond.Where("a", 1, EQUAL), Cond.Where("b", 2, EQUAL)),
+//		Cond.Not(Cond.Where("c", 3, EQUAL)),
+//	)
+var Cond ConditionDSL
+
+// ConditionDSL creates and combines conditions, use the Cond value
+type ConditionDSL struct{}
+
+// Where creates a single condition on the attribute using the operator
+func (ConditionDSL) Where(name string, value interface{}, operator Operator) Condition {
+	builder, err := createCondition(name, value, operator)
+	return Condition{builder: builder, err: validationErr(name, err)}
+}
+
+// And combines the conditions with AND
+func (ConditionDSL) And(conditions ...Condition) Condition {
+	return combineConditions(conditions, expression.And)
+}
+
+// Or combines the conditions with OR
+func (ConditionDSL) Or(conditions ...Condition) Condition {
+	return combineConditions(conditions, expression.Or)
+}
+
+// Not negates the condition
+func (ConditionDSL) Not(condition Condition) Condition {
+	if condition.err != nil {
+		return condition
+	}
+	return Condition{builder: expression.Not(condition.builder)}
+}
+
+// And combines the condition with the others using AND
+func (c Condition) And(conditions ...Condition) Condition {
+	return Cond.And(append([]Condition{c}, conditions...)...)
+}
+
+// Or combines the condition with the others using OR
+func (c Condition) Or(conditions ...Condition) Condition {
+	return Cond.Or(append([]Condition{c}, conditions...)...)
+}
+
+// Not negates the condition
+func (c Condition) Not() Condition {
+	return Cond.Not(c)
+}
+
+// Err returns the error of the first invalid condition in the group if any
+func (c Condition) Err() error {
+	return c.err
+}
+
+// combineConditions combines the conditions using the provided logical operator, a single condition is returned as is
+func combineConditions(
+	conditions []Condition,
+	combine func(left, right expression.ConditionBuilder, other ...expression.ConditionBuilder) expression.ConditionBuilder,
+) Condition {
+	if len(conditions) == 0 {
+		return Condition{err: newValidationErr("condition", "missing conditions to combine")}
+	}
+	builders := make([]expression.ConditionBuilder, 0, len(conditions))
+	for _, condition := range conditions {
+		if condition.err != nil {
+			return condition
+		}
+		builders = append(builders, condition.builder)
+	}
+	if len(builders) == 1 {
+		return conditions[0]
+	}
+	return Condition{builder: combine(builders[0], builders[1], builders[2:]...)}
+}
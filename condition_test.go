@@ -0,0 +1,94 @@
+package dynamodb_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sghaida/dyorm"
+)
+
+func TestCondition(t *testing.T) {
+	cond := dynamodb.Cond
+	a := cond.Where("a", 1, dynamodb.EQUAL)
+	b := cond.Where("b", 2, dynamodb.EQUAL)
+	c := cond.Where("c", 3, dynamodb.EQUAL)
+
+	cases := []struct {
+		name      string
+		condition dynamodb.Condition
+		expected  string
+		hasError  bool
+	}{
+		{
+			name:      "or group with and",
+			condition: cond.And(cond.Or(a, b), c),
+			expected:  "((#0 = :0) OR (#1 = :1)) AND (#2 = :2)",
+		},
+		{
+			name:      "and group with or",
+			condition: cond.Or(a, cond.And(b, c)),
+			expected:  "(#0 = :0) OR ((#1 = :1) AND (#2 = :2))",
+		},
+		{
+			name:      "not",
+			condition: cond.And(a, cond.Not(cond.Or(b, c))),
+			expected:  "(#0 = :0) AND (NOT ((#1 = :1) OR (#2 = :2)))",
+		},
+		{
+			name:      "chained",
+			condition: a.Or(b).And(c.Not()),
+			expected:  "((#0 = :0) OR (#1 = :1)) AND (NOT (#2 = :2))",
+		},
+		{
+			name:      "single condition",
+			condition: cond.And(a),
+			expected:  "#0 = :0",
+		},
+		{
+			name:      "without conditions",
+			condition: cond.Or(),
+			hasError:  true,
+		},
+		{
+			name:      "with invalid nested condition",
+			condition: cond.And(a, cond.Not(cond.Where("b", 2, dynamodb.BEGINSWITH))),
+			hasError:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.hasError, tc.condition.Err() != nil)
+
+			input, err := dynamodb.NewExpressionWrapper("request-test").
+				WithConditionGroup(tc.condition).
+				BuildScanInput()
+			if tc.hasError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, aws.StringValue(input.FilterExpression))
+		})
+	}
+
+	t.Run("combined with simple conditions", func(t *testing.T) {
+		input, err := dynamodb.NewExpressionWrapper("request-test").
+			WithPartitionKey("partitionID", "1234").
+			WithCondition("a", 1, dynamodb.EQUAL).
+			AndConditionGroup(cond.Or(b, c)).
+			BuildDeleteInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "(#0 = :0) AND ((#1 = :1) OR (#2 = :2))", aws.StringValue(input.ConditionExpression))
+
+		input, err = dynamodb.NewExpressionWrapper("request-test").
+			WithPartitionKey("partitionID", "1234").
+			OrConditionGroup(cond.Not(a)).
+			BuildDeleteInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "NOT (#0 = :0)", aws.StringValue(input.ConditionExpression))
+	})
+}
@@ -1,41 +1,221 @@
 package dynamodb
 
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+const (
+	defaultBatchWriteConcurrency = 4
+	defaultBatchWriteDeadline    = 30 * time.Second
+	defaultBatchWriteBaseBackoff = 50 * time.Millisecond
+	defaultBatchWriteMaxBackoff  = 5 * time.Second
+)
+
 // DynamoTableOrIndexName define the dynamo table index ( LSI or GSI)
 type DynamoTableOrIndexName string
 
 // DBKeyName a type for dynamo partition key or sorting key
 type DBKeyName string
 
+// DBKeyType the dynamodb scalar type of a partition or sort key
+type DBKeyType string
+
+const (
+	// KeyTypeString string keys, the default if the key type is not set
+	KeyTypeString DBKeyType = dynamodb.ScalarAttributeTypeS
+	// KeyTypeNumber number keys, the key value holds the decimal representation eg. "42"
+	KeyTypeNumber DBKeyType = dynamodb.ScalarAttributeTypeN
+	// KeyTypeBinary binary keys, the key value holds the raw bytes eg. DBKeyValue([]byte{1, 2})
+	KeyTypeBinary DBKeyType = dynamodb.ScalarAttributeTypeB
+)
+
+// IsValid checks if the key type is supported, the empty type is treated as KeyTypeString
+func (t DBKeyType) IsValid() bool {
+	switch t {
+	case "", KeyTypeString, KeyTypeNumber, KeyTypeBinary:
+		return true
+	default:
+		return false
+	}
+}
+
+// AttributeValue converts the key value to an attribute value of the key type
+func (t DBKeyType) AttributeValue(value DBKeyValue) *dynamodb.AttributeValue {
+	switch t {
+	case KeyTypeNumber:
+		return &dynamodb.AttributeValue{N: aws.String(string(value))}
+	case KeyTypeBinary:
+		return &dynamodb.AttributeValue{B: []byte(value)}
+	default:
+		return &dynamodb.AttributeValue{S: aws.String(string(value))}
+	}
+}
+
 // DBPSKeyNames hold the attribute name(s) for a table or a table index' s partition and sort keys
+// along with their scalar types, the types default to KeyTypeString if not set
+// LocalIndex marks a local secondary index, the indexes are otherwise treated as global ones
+// which do not support consistent reads, it is ignored for the table keys
 type DBPSKeyNames struct {
-	PartitionKey DBKeyName
-	SortKey      *DBKeyName
+	PartitionKey     DBKeyName
+	SortKey          *DBKeyName
+	PartitionKeyType DBKeyType
+	SortKeyType      DBKeyType
+	LocalIndex       bool
+}
+
+// isValid checks if the partition key is set and the key types are supported
+func (k DBPSKeyNames) isValid() bool {
+	return len(k.PartitionKey) > 0 && k.PartitionKeyType.IsValid() && k.SortKeyType.IsValid()
 }
 
 // DBTableInfo holds the TableName, Partition key and sorting key if available
+// TypeAttribute if set names the attribute holding the model type of the records (see BaseModel.GetModelType)
+// in single table designs: it is written on every put, the queries and scans are filtered by the model type
+// and the records of another model type are not unmarshalled
 type DBTableInfo struct {
 	TableName string
 	DBPSKeyNames
+	TypeAttribute FieldName
+}
+
+// BatchWriteConfig defines how the bulk commands write and retry the records
+// zero values are replaced with the defaults
+type BatchWriteConfig struct {
+	// MaxConcurrency the maximum number of BatchWriteItem calls executed concurrently
+	MaxConcurrency int
+	// RetryDeadline the maximum time spent retrying the unprocessed items
+	RetryDeadline time.Duration
+	// BaseBackoff the wait before the first retry, it is doubled on every retry up to MaxBackoff
+	BaseBackoff time.Duration
+	// MaxBackoff the maximum wait between two retries
+	MaxBackoff time.Duration
+}
+
+// withDefaults returns a copy of the config where the missing values are set to the defaults
+func (c BatchWriteConfig) withDefaults() BatchWriteConfig {
+	if c.MaxConcurrency < 1 {
+		c.MaxConcurrency = defaultBatchWriteConcurrency
+	}
+	if c.RetryDeadline <= 0 {
+		c.RetryDeadline = defaultBatchWriteDeadline
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = defaultBatchWriteBaseBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaultBatchWriteMaxBackoff
+	}
+	return c
+}
+
+// DBTableConfig holds the table name and keys along with its indexes and the types of the models stored in the table
+type DBTableConfig struct {
+	TableInfo DBTableInfo
+	Indexes   map[DynamoTableOrIndexName]DBPSKeyNames
+	Models    []DBModelName
+}
+
+// isValid checks if the table, its indexes and its models are set
+func (t DBTableConfig) isValid() bool {
+	if len(t.TableInfo.TableName) < 1 || !t.TableInfo.isValid() || len(t.Models) < 1 {
+		return false
+	}
+	for _, dbIndex := range t.Indexes {
+		if !dbIndex.isValid() {
+			return false
+		}
+	}
+	return true
 }
 
 // DBConfig define the database config type
 // hold the main table name, partition key, and sorting key if available
 // along with all the info for the indices keyed by the Index name
 // and the value for the indices map is the partition key, sorting key if available
+// Tables maps the model types (see BaseModel.GetModelType) to the other tables, the handler routes the calls
+// of these models to their table, the models that are not registered use the main table
+// CursorSecret if provided is used to sign and verify the pagination cursors
+// BatchWrite defines the concurrency and the retries of the bulk commands
 type DBConfig struct {
-	TableInfo DBTableInfo
-	Indexes   map[DynamoTableOrIndexName]DBPSKeyNames
+	TableInfo    DBTableInfo
+	Indexes      map[DynamoTableOrIndexName]DBPSKeyNames
+	Tables       []DBTableConfig
+	CursorSecret []byte
+	BatchWrite   BatchWriteConfig
+}
+
+// Table returns the table config of the models eg. to add the config returned by RegisterModel to Tables
+func (c DBConfig) Table(models ...DBModelName) DBTableConfig {
+	return DBTableConfig{
+		TableInfo: c.TableInfo,
+		Indexes:   c.Indexes,
+		Models:    models,
+	}
+}
+
+// modelTable returns the table the model type is registered to
+func (c DBConfig) modelTable(modelType DBModelName) (DBTableConfig, bool) {
+	for _, table := range c.Tables {
+		for _, model := range table.Models {
+			if model == modelType {
+				return table, true
+			}
+		}
+	}
+	return DBTableConfig{}, false
+}
+
+// namedTable returns the registered table of the name
+func (c DBConfig) namedTable(name string) (DBTableConfig, bool) {
+	for _, table := range c.Tables {
+		if table.TableInfo.TableName == name {
+			return table, true
+		}
+	}
+	return DBTableConfig{}, false
+}
+
+// withTable returns a copy of the config using the table as the main table
+func (c DBConfig) withTable(table DBTableConfig) DBConfig {
+	c.TableInfo = table.TableInfo
+	c.Indexes = table.Indexes
+	return c
+}
+
+// isGlobalIndex checks if the index is a global secondary index, the indexes not marked as LocalIndex are global
+func (c DBConfig) isGlobalIndex(name DynamoTableOrIndexName) bool {
+	if name == "" {
+		return false
+	}
+	keys, ok := c.Indexes[name]
+	return !ok || !keys.LocalIndex
 }
 
 // IsValid check if the configuration is valid
 func (c DBConfig) IsValid() bool {
-	if len(c.TableInfo.TableName) < 1 || len(c.TableInfo.PartitionKey) < 1 {
+	if len(c.TableInfo.TableName) < 1 || !c.TableInfo.isValid() {
 		return false
 	}
 	for _, dbIndex := range c.Indexes {
-		if len(dbIndex.PartitionKey) < 1 {
+		if !dbIndex.isValid() {
 			return false
 		}
 	}
+	// a model is stored in a single table
+	models := make(map[DBModelName]bool)
+	for _, table := range c.Tables {
+		if !table.isValid() {
+			return false
+		}
+		for _, model := range table.Models {
+			if models[model] {
+				return false
+			}
+			models[model] = true
+		}
+	}
 	return true
 }
@@ -3,9 +3,17 @@ package dynamodb
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
 )
 
+// ordersTable the table of the order model, keyed by the order id
+var ordersTable = DBTableConfig{
+	TableInfo: DBTableInfo{TableName: "orders", DBPSKeyNames: DBPSKeyNames{PartitionKey: "order_id"}},
+	Indexes:   map[DynamoTableOrIndexName]DBPSKeyNames{"by_user": {PartitionKey: "user_id"}},
+	Models:    []DBModelName{"order"},
+}
+
 func TestDbConfig_IsValid(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -71,6 +79,63 @@ func TestDbConfig_IsValid(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "successfully with typed keys",
+			config: DBConfig{
+				TableInfo: DBTableInfo{
+					TableName: "table",
+					DBPSKeyNames: DBPSKeyNames{
+						PartitionKey:     pKey,
+						SortKey:          &sKey,
+						PartitionKeyType: KeyTypeNumber,
+						SortKeyType:      KeyTypeBinary,
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "with unsupported key type",
+			config: DBConfig{
+				TableInfo: DBTableInfo{
+					TableName: "table",
+					DBPSKeyNames: DBPSKeyNames{
+						PartitionKey: pKey,
+					},
+				},
+				Indexes: map[DynamoTableOrIndexName]DBPSKeyNames{
+					"index": {
+						PartitionKey:     pKey,
+						PartitionKeyType: "BOOL",
+					},
+				},
+			},
+		},
+		{
+			name: "successfully with registered tables",
+			config: DBConfig{
+				TableInfo: cfg.TableInfo,
+				Tables:    []DBTableConfig{ordersTable},
+			},
+			expected: true,
+		},
+		{
+			name: "with a table without models",
+			config: DBConfig{
+				TableInfo: cfg.TableInfo,
+				Tables:    []DBTableConfig{{TableInfo: ordersTable.TableInfo}},
+			},
+		},
+		{
+			name: "with a model registered to two tables",
+			config: DBConfig{
+				TableInfo: cfg.TableInfo,
+				Tables: []DBTableConfig{ordersTable, {
+					TableInfo: DBTableInfo{TableName: "archive", DBPSKeyNames: ordersTable.TableInfo.DBPSKeyNames},
+					Models:    []DBModelName{"order"},
+				}},
+			},
+		},
 		{
 			name: "with missing table name",
 			config: DBConfig{
@@ -98,3 +163,67 @@ func TestDbConfig_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestDBKeyType_AttributeValue(t *testing.T) {
+	str := DBKeyType("").AttributeValue("key")
+	assert.Equal(t, "key", aws.StringValue(str.S))
+	assert.Equal(t, DBKeyValue("key"), keyValueOf(str))
+
+	num := KeyTypeNumber.AttributeValue("42")
+	assert.Nil(t, num.S)
+	assert.Equal(t, "42", aws.StringValue(num.N))
+	assert.Equal(t, DBKeyValue("42"), keyValueOf(num))
+
+	bin := KeyTypeBinary.AttributeValue(DBKeyValue([]byte{1, 2}))
+	assert.Nil(t, bin.S)
+	assert.Equal(t, []byte{1, 2}, bin.B)
+	assert.Equal(t, DBKeyValue([]byte{1, 2}), keyValueOf(bin))
+
+	assert.Empty(t, keyValueOf(nil))
+}
+
+// orderModel a model stored in the orders table
+type orderModel struct {
+	TestBaseModel
+}
+
+// GetModelType returns the model type
+func (orderModel) GetModelType() DBModelName {
+	return "order"
+}
+
+func TestHandlerImp_route(t *testing.T) {
+	config := cfg
+	config.Tables = []DBTableConfig{ordersTable}
+	repo := handlerImp{config: config}
+	order := orderModel{}
+
+	cases := []struct {
+		name     string
+		model    BaseModel
+		expr     *AwsExpressionWrapper
+		expected string
+	}{
+		{name: "unregistered model", model: TestBaseModel{}, expected: "table"},
+		{name: "registered model", model: order, expected: "orders"},
+		{name: "registered table", expr: NewExpressionWrapper("orders"), expected: "orders"},
+		{name: "main table", model: order, expr: NewExpressionWrapper("table"), expected: "table"},
+		{name: "unknown table", model: order, expr: NewExpressionWrapper("unknown"), expected: "orders"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			routed := repo.route(tc.model, tc.expr)
+			assert.Equal(t, tc.expected, routed.config.TableInfo.TableName)
+		})
+	}
+
+	routed := repo.ForModel("order").(handlerImp)
+	assert.Equal(t, ordersTable.TableInfo, routed.config.TableInfo)
+	assert.Equal(t, ordersTable.Indexes, routed.config.Indexes)
+	assert.Equal(t, cfg.TableInfo, repo.ForModel("user").(handlerImp).config.TableInfo)
+
+	users, err := RegisterModel[taggedUser]("user", "users")
+	assert.NoError(t, err)
+	assert.Equal(t, DBTableConfig{TableInfo: users.TableInfo, Indexes: users.Indexes, Models: []DBModelName{"user"}}, users.Table("user"))
+}
@@ -0,0 +1,78 @@
+package dynamodb
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// cursorPayload the content of a pagination cursor
+type cursorPayload struct {
+	TableName string                              `json:"t"`
+	IndexName string                              `json:"i,omitempty"`
+	Key       map[string]*dynamodb.AttributeValue `json:"k"`
+}
+
+// encodeCursor encodes the last evaluated key into an opaque cursor bound to the table and index,
+// the cursor is signed using HMAC-SHA256 if a secret is provided
+// returns an empty cursor if there is no last evaluated key
+func encodeCursor(lastKey DBAttributeValues, tableName, indexName string, secret []byte) (string, error) {
+	if len(lastKey) == 0 {
+		return "", nil
+	}
+
+	payload, err := json.Marshal(cursorPayload{
+		TableName: tableName,
+		IndexName: indexName,
+		Key:       lastKey,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	cursor := base64.RawURLEncoding.EncodeToString(payload)
+	if len(secret) > 0 {
+		cursor += "." + base64.RawURLEncoding.EncodeToString(signCursor(payload, secret))
+	}
+	return cursor, nil
+}
+
+// decodeCursor decodes and verifies the cursor, then returns the exclusive start key
+// it rejects cursors that were tampered with or issued for a different table or index
+func decodeCursor(cursor, tableName, indexName string, secret []byte) (map[string]*dynamodb.AttributeValue, error) {
+	encodedPayload, encodedSignature, signed := strings.Cut(cursor, ".")
+	if signed != (len(secret) > 0) {
+		return nil, newValidationErr("cursor", "invalid cursor signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, newValidationErr("cursor", "invalid cursor encoding")
+	}
+
+	if signed {
+		signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+		if err != nil || !hmac.Equal(signature, signCursor(payload, secret)) {
+			return nil, newValidationErr("cursor", "invalid cursor signature")
+		}
+	}
+
+	decoded := cursorPayload{}
+	if err := json.Unmarshal(payload, &decoded); err != nil || len(decoded.Key) == 0 {
+		return nil, newValidationErr("cursor", "invalid cursor content")
+	}
+	if decoded.TableName != tableName || decoded.IndexName != indexName {
+		return nil, newValidationErr("cursor", "cursor was issued for a different table or index")
+	}
+	return decoded.Key, nil
+}
+
+func signCursor(payload, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
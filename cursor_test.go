@@ -0,0 +1,248 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	lastKey := DBAttributeValues{
+		"partKey": {S: aws.String("part")},
+		"sortKey": {N: aws.String("12")},
+	}
+	secret := []byte("secret")
+
+	cases := []struct {
+		name          string
+		encodeSecret  []byte
+		decodeSecret  []byte
+		decodeTable   string
+		decodeIndex   string
+		tamper        func(cursor string) string
+		hasError      bool
+		expectedEmpty bool
+	}{
+		{
+			name:        "successfully without secret",
+			decodeTable: "table",
+			decodeIndex: "index",
+		},
+		{
+			name:         "successfully with secret",
+			encodeSecret: secret,
+			decodeSecret: secret,
+			decodeTable:  "table",
+			decodeIndex:  "index",
+		},
+		{
+			name:         "with wrong secret",
+			encodeSecret: secret,
+			decodeSecret: []byte("other"),
+			decodeTable:  "table",
+			decodeIndex:  "index",
+			hasError:     true,
+		},
+		{
+			name:         "unsigned cursor when a secret is configured",
+			decodeSecret: secret,
+			decodeTable:  "table",
+			decodeIndex:  "index",
+			hasError:     true,
+		},
+		{
+			name:         "signed cursor without a configured secret",
+			encodeSecret: secret,
+			decodeTable:  "table",
+			decodeIndex:  "index",
+			hasError:     true,
+		},
+		{
+			name:         "with tampered payload",
+			encodeSecret: secret,
+			decodeSecret: secret,
+			decodeTable:  "table",
+			decodeIndex:  "index",
+			tamper: func(cursor string) string {
+				return "e30" + cursor[3:]
+			},
+			hasError: true,
+		},
+		{
+			name:        "issued for a different table",
+			decodeTable: "other",
+			decodeIndex: "index",
+			hasError:    true,
+		},
+		{
+			name:        "issued for a different index",
+			decodeTable: "table",
+			hasError:    true,
+		},
+		{
+			name:        "with invalid encoding",
+			decodeTable: "table",
+			decodeIndex: "index",
+			tamper: func(string) string {
+				return "%%%"
+			},
+			hasError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := encodeCursor(lastKey, "table", "index", tc.encodeSecret)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, cursor)
+			if tc.tamper != nil {
+				cursor = tc.tamper(cursor)
+			}
+
+			key, err := decodeCursor(cursor, tc.decodeTable, tc.decodeIndex, tc.decodeSecret)
+			assert.Equal(t, tc.hasError, err != nil)
+			if !tc.hasError {
+				assert.Equal(t, "part", aws.StringValue(key["partKey"].S))
+				assert.Equal(t, "12", aws.StringValue(key["sortKey"].N))
+			}
+		})
+	}
+
+	t.Run("without last evaluated key", func(t *testing.T) {
+		cursor, err := encodeCursor(nil, "table", "", secret)
+		assert.NoError(t, err)
+		assert.Empty(t, cursor)
+	})
+}
+
+func TestAwsExpressionWrapper_WithCursor(t *testing.T) {
+	lastKey := DBAttributeValues{"partKey": {S: aws.String("part")}}
+	cursor, _ := encodeCursor(lastKey, "table", "index", nil)
+
+	t.Run("query input", func(t *testing.T) {
+		input, err := NewExpressionWrapper("table").
+			WithIndexName("index").
+			WithKeyCondition("partKey", "part", EQUAL).
+			WithCursor(cursor).
+			BuildQueryInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "part", aws.StringValue(input.ExclusiveStartKey["partKey"].S))
+	})
+
+	t.Run("scan input for a different index", func(t *testing.T) {
+		_, err := NewExpressionWrapper("table").
+			WithCursor(cursor).
+			BuildScanInput()
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+}
+
+func TestHandlerImp_GetRecordsWithCursor(t *testing.T) {
+	lastKey := map[string]*dynamodb.AttributeValue{
+		"partKey": {S: aws.String("b")},
+	}
+	config := cfg
+	config.CursorSecret = []byte("secret")
+
+	t.Run("query cursor round trip", func(t *testing.T) {
+		repo := handlerImp{
+			config: config,
+			DynamoDBAPI: &MockPagedQuery{
+				Pages: []dynamodb.QueryOutput{
+					{Items: createIteratorPageItems("a"), LastEvaluatedKey: lastKey},
+					{Items: createIteratorPageItems("b")},
+				},
+			},
+		}
+		ctx := context.Background()
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithKeyCondition("pName", "pValue", EQUAL)
+
+		res, cursor, err := repo.GetRecordsWithQueryCursor(ctx, TestBaseModel{}, filters)
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.NotEmpty(t, cursor)
+
+		filters = NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithKeyCondition("pName", "pValue", EQUAL).
+			WithCursor(cursor)
+		res, cursor, err = repo.GetRecordsWithQueryCursor(ctx, TestBaseModel{}, filters)
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.Empty(t, cursor)
+		// the secret of the handler is not set on the caller filters
+		assert.Nil(t, filters.cursorSecret)
+	})
+
+	t.Run("scan cursor", func(t *testing.T) {
+		repo := handlerImp{
+			config: config,
+			DynamoDBAPI: MockScan{
+				Resp: dynamodb.ScanOutput{
+					Items:            createIteratorPageItems("a"),
+					LastEvaluatedKey: lastKey,
+				},
+			},
+		}
+		ctx := context.Background()
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName)
+
+		res, cursor, err := repo.GetRecordsWithScanCursor(ctx, TestBaseModel{}, filters)
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.NotEmpty(t, cursor)
+	})
+
+	t.Run("with forged cursor", func(t *testing.T) {
+		repo := handlerImp{
+			config:      config,
+			DynamoDBAPI: MockScan{},
+		}
+		forged, _ := encodeCursor(lastKey, cfg.TableInfo.TableName, "", []byte("forged"))
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName).WithCursor(forged)
+
+		_, _, err := repo.GetRecordsWithScanCursor(context.Background(), TestBaseModel{}, filters)
+		var valErr *ValidationError
+		assert.True(t, errors.As(err, &valErr))
+		assert.Equal(t, "cursor", valErr.Field)
+	})
+
+	t.Run("with db error", func(t *testing.T) {
+		repo := handlerImp{
+			config:      config,
+			DynamoDBAPI: MockQuery{Err: assert.AnError},
+		}
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithKeyCondition("pName", "pValue", EQUAL)
+
+		_, cursor, err := repo.GetRecordsWithQueryCursor(context.Background(), TestBaseModel{}, filters)
+		assert.Error(t, err)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("iterator starts from cursor", func(t *testing.T) {
+		mock := &MockPagedQuery{
+			Pages: []dynamodb.QueryOutput{
+				{Items: createIteratorPageItems("b")},
+			},
+		}
+		repo := handlerImp{config: config, DynamoDBAPI: mock}
+		cursor, _ := encodeCursor(lastKey, cfg.TableInfo.TableName, "", config.CursorSecret)
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithKeyCondition("pName", "pValue", EQUAL).
+			WithCursor(cursor)
+
+		it := repo.QueryIterator(context.Background(), TestBaseModel{}, filters)
+		count := 0
+		for it.Next() {
+			count++
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, 1, count)
+	})
+}
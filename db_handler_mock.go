@@ -37,6 +37,27 @@ func (_m *MockDBHandler) AddRecord(ctx context.Context, in BaseModel, createSort
 	return r0, r1
 }
 
+// BatchGetByIDs provides a mock function with given fields: ctx, input, dbKeys, options
+func (_m *MockDBHandler) BatchGetByIDs(ctx context.Context, input BaseModel, dbKeys []DBPSKeyValues, options *AwsExpressionWrapper) (BatchGetResult, error) {
+	ret := _m.Called(ctx, input, dbKeys, options)
+
+	var r0 BatchGetResult
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, []DBPSKeyValues, *AwsExpressionWrapper) BatchGetResult); ok {
+		r0 = rf(ctx, input, dbKeys, options)
+	} else {
+		r0 = ret.Get(0).(BatchGetResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, BaseModel, []DBPSKeyValues, *AwsExpressionWrapper) error); ok {
+		r1 = rf(ctx, input, dbKeys, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BulkAddRecords provides a mock function with given fields: ctx, baseModel, createSortKey, records
 func (_m *MockDBHandler) BulkAddRecords(ctx context.Context, baseModel BaseModel, createSortKey bool, records ...BaseModel) ([]BaseModel, error) {
 	_va := make([]interface{}, len(records))
@@ -141,6 +162,45 @@ func (_m *MockDBHandler) DeleteRecordByID(ctx context.Context, dbKeys DBPSKeyVal
 	return r0
 }
 
+// FindByID provides a mock function with given fields: ctx, input, name, dbKeys, options
+func (_m *MockDBHandler) FindByID(ctx context.Context, input BaseModel, name DynamoTableOrIndexName, dbKeys DBPSKeyValues, options *AwsExpressionWrapper) (BaseModel, error) {
+	ret := _m.Called(ctx, input, name, dbKeys, options)
+
+	var r0 BaseModel
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, DynamoTableOrIndexName, DBPSKeyValues, *AwsExpressionWrapper) BaseModel); ok {
+		r0 = rf(ctx, input, name, dbKeys, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(BaseModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, BaseModel, DynamoTableOrIndexName, DBPSKeyValues, *AwsExpressionWrapper) error); ok {
+		r1 = rf(ctx, input, name, dbKeys, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForModel provides a mock function with given fields: modelType
+func (_m *MockDBHandler) ForModel(modelType DBModelName) DBHandler {
+	ret := _m.Called(modelType)
+
+	var r0 DBHandler
+	if rf, ok := ret.Get(0).(func(DBModelName) DBHandler); ok {
+		r0 = rf(modelType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(DBHandler)
+		}
+	}
+
+	return r0
+}
+
 // GetByID provides a mock function with given fields: ctx, input, name, dbKeys
 func (_m *MockDBHandler) GetByID(ctx context.Context, input BaseModel, name DynamoTableOrIndexName, dbKeys DBPSKeyValues) (BaseModel, error) {
 	ret := _m.Called(ctx, input, name, dbKeys)
@@ -187,6 +247,59 @@ func (_m *MockDBHandler) GetByIDs(ctx context.Context, input BaseModel, dbKeys [
 	return r0, r1
 }
 
+// GetByIDsWithOptions provides a mock function with given fields: ctx, input, dbKeys, options
+func (_m *MockDBHandler) GetByIDsWithOptions(ctx context.Context, input BaseModel, dbKeys []DBPSKeyValues, options *AwsExpressionWrapper) ([]BaseModel, error) {
+	ret := _m.Called(ctx, input, dbKeys, options)
+
+	var r0 []BaseModel
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, []DBPSKeyValues, *AwsExpressionWrapper) []BaseModel); ok {
+		r0 = rf(ctx, input, dbKeys, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]BaseModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, BaseModel, []DBPSKeyValues, *AwsExpressionWrapper) error); ok {
+		r1 = rf(ctx, input, dbKeys, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRecordsWithQueryCursor provides a mock function with given fields: ctx, input, filters
+func (_m *MockDBHandler) GetRecordsWithQueryCursor(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, string, error) {
+	ret := _m.Called(ctx, input, filters)
+
+	var r0 []BaseModel
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, *AwsExpressionWrapper) []BaseModel); ok {
+		r0 = rf(ctx, input, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]BaseModel)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, BaseModel, *AwsExpressionWrapper) string); ok {
+		r1 = rf(ctx, input, filters)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, BaseModel, *AwsExpressionWrapper) error); ok {
+		r2 = rf(ctx, input, filters)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetRecordsWithQueryFilter provides a mock function with given fields: ctx, input, filters
 func (_m *MockDBHandler) GetRecordsWithQueryFilter(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, DBAttributeValues, error) {
 	ret := _m.Called(ctx, input, filters)
@@ -219,6 +332,36 @@ func (_m *MockDBHandler) GetRecordsWithQueryFilter(ctx context.Context, input Ba
 	return r0, r1, r2
 }
 
+// GetRecordsWithScanCursor provides a mock function with given fields: ctx, input, filters
+func (_m *MockDBHandler) GetRecordsWithScanCursor(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, string, error) {
+	ret := _m.Called(ctx, input, filters)
+
+	var r0 []BaseModel
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, *AwsExpressionWrapper) []BaseModel); ok {
+		r0 = rf(ctx, input, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]BaseModel)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, BaseModel, *AwsExpressionWrapper) string); ok {
+		r1 = rf(ctx, input, filters)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, BaseModel, *AwsExpressionWrapper) error); ok {
+		r2 = rf(ctx, input, filters)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetRecordsWithScanFilter provides a mock function with given fields: ctx, input, filters
 func (_m *MockDBHandler) GetRecordsWithScanFilter(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, DBAttributeValues, error) {
 	ret := _m.Called(ctx, input, filters)
@@ -251,6 +394,204 @@ func (_m *MockDBHandler) GetRecordsWithScanFilter(ctx context.Context, input Bas
 	return r0, r1, r2
 }
 
+// ParallelScan provides a mock function with given fields: ctx, input, filters, totalSegments, resume, fn
+func (_m *MockDBHandler) ParallelScan(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper, totalSegments int64, resume []ScanSegment, fn ScanPageFunc) error {
+	ret := _m.Called(ctx, input, filters, totalSegments, resume, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, *AwsExpressionWrapper, int64, []ScanSegment, ScanPageFunc) error); ok {
+		r0 = rf(ctx, input, filters, totalSegments, resume, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueryIterator provides a mock function with given fields: ctx, input, filters
+func (_m *MockDBHandler) QueryIterator(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) *RecordIterator {
+	ret := _m.Called(ctx, input, filters)
+
+	var r0 *RecordIterator
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, *AwsExpressionWrapper) *RecordIterator); ok {
+		r0 = rf(ctx, input, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*RecordIterator)
+		}
+	}
+
+	return r0
+}
+
+// Query provides a mock function with given fields: ctx, input, index, partitionValue, sortKey
+func (_m *MockDBHandler) Query(ctx context.Context, input BaseModel, index DynamoTableOrIndexName, partitionValue DBKeyValue, sortKey *SortKeyCondition) ([]BaseModel, DBAttributeValues, error) {
+	ret := _m.Called(ctx, input, index, partitionValue, sortKey)
+
+	var r0 []BaseModel
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, DynamoTableOrIndexName, DBKeyValue, *SortKeyCondition) []BaseModel); ok {
+		r0 = rf(ctx, input, index, partitionValue, sortKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]BaseModel)
+		}
+	}
+
+	var r1 DBAttributeValues
+	if rf, ok := ret.Get(1).(func(context.Context, BaseModel, DynamoTableOrIndexName, DBKeyValue, *SortKeyCondition) DBAttributeValues); ok {
+		r1 = rf(ctx, input, index, partitionValue, sortKey)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(DBAttributeValues)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, BaseModel, DynamoTableOrIndexName, DBKeyValue, *SortKeyCondition) error); ok {
+		r2 = rf(ctx, input, index, partitionValue, sortKey)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// QueryModels provides a mock function with given fields: ctx, registry, filters
+func (_m *MockDBHandler) QueryModels(ctx context.Context, registry ModelRegistry, filters *AwsExpressionWrapper) ([]BaseModel, DBAttributeValues, error) {
+	ret := _m.Called(ctx, registry, filters)
+
+	var r0 []BaseModel
+	if rf, ok := ret.Get(0).(func(context.Context, ModelRegistry, *AwsExpressionWrapper) []BaseModel); ok {
+		r0 = rf(ctx, registry, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]BaseModel)
+		}
+	}
+
+	var r1 DBAttributeValues
+	if rf, ok := ret.Get(1).(func(context.Context, ModelRegistry, *AwsExpressionWrapper) DBAttributeValues); ok {
+		r1 = rf(ctx, registry, filters)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(DBAttributeValues)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, ModelRegistry, *AwsExpressionWrapper) error); ok {
+		r2 = rf(ctx, registry, filters)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ScanIterator provides a mock function with given fields: ctx, input, filters
+func (_m *MockDBHandler) ScanIterator(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) *RecordIterator {
+	ret := _m.Called(ctx, input, filters)
+
+	var r0 *RecordIterator
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, *AwsExpressionWrapper) *RecordIterator); ok {
+		r0 = rf(ctx, input, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*RecordIterator)
+		}
+	}
+
+	return r0
+}
+
+// TransactGetByIDs provides a mock function with given fields: ctx, items
+func (_m *MockDBHandler) TransactGetByIDs(ctx context.Context, items ...TransactGetItem) ([]BaseModel, error) {
+	_va := make([]interface{}, len(items))
+	for _i := range items {
+		_va[_i] = items[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []BaseModel
+	if rf, ok := ret.Get(0).(func(context.Context, ...TransactGetItem) []BaseModel); ok {
+		r0 = rf(ctx, items...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]BaseModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, ...TransactGetItem) error); ok {
+		r1 = rf(ctx, items...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactWrite provides a mock function with given fields: ctx, requestToken, items
+func (_m *MockDBHandler) TransactWrite(ctx context.Context, requestToken string, items ...TransactWriteItem) error {
+	_va := make([]interface{}, len(items))
+	for _i := range items {
+		_va[_i] = items[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, requestToken)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...TransactWriteItem) error); ok {
+		r0 = rf(ctx, requestToken, items...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAndReturn provides a mock function with given fields: ctx, input, dbKeys, update
+func (_m *MockDBHandler) UpdateAndReturn(ctx context.Context, input BaseModel, dbKeys DBPSKeyValues, update *AwsExpressionWrapper) (BaseModel, error) {
+	ret := _m.Called(ctx, input, dbKeys, update)
+
+	var r0 BaseModel
+	if rf, ok := ret.Get(0).(func(context.Context, BaseModel, DBPSKeyValues, *AwsExpressionWrapper) BaseModel); ok {
+		r0 = rf(ctx, input, dbKeys, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(BaseModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, BaseModel, DBPSKeyValues, *AwsExpressionWrapper) error); ok {
+		r1 = rf(ctx, input, dbKeys, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateByID provides a mock function with given fields: ctx, dbKeys, update
+func (_m *MockDBHandler) UpdateByID(ctx context.Context, dbKeys DBPSKeyValues, update *AwsExpressionWrapper) error {
+	ret := _m.Called(ctx, dbKeys, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, DBPSKeyValues, *AwsExpressionWrapper) error); ok {
+		r0 = rf(ctx, dbKeys, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateRecordByID provides a mock function with given fields: ctx, in, dbKeys
 func (_m *MockDBHandler) UpdateRecordByID(ctx context.Context, in BaseModel, dbKeys DBPSKeyValues) error {
 	ret := _m.Called(ctx, in, dbKeys)
@@ -1,7 +1,10 @@
 package dynamodb
 
 import (
+	"sync"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
@@ -90,13 +93,18 @@ type MockDeleteItem struct {
 	dynamodbiface.DynamoDBAPI
 	Resp dynamodb.DeleteItemOutput
 	Err  error
+	// TableName if set, the deletes of the other tables fail with ResourceNotFoundException
+	TableName string
 }
 
 // DeleteItemWithContext mocks dynamodb's DeleteItemWithContext
-func (d MockDeleteItem) DeleteItemWithContext(aws.Context, *dynamodb.DeleteItemInput, ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+func (d MockDeleteItem) DeleteItemWithContext(_ aws.Context, in *dynamodb.DeleteItemInput, _ ...request.Option) (*dynamodb.DeleteItemOutput, error) {
 	if d.Err != nil {
 		return nil, d.Err
 	}
+	if d.TableName != "" && aws.StringValue(in.TableName) != d.TableName {
+		return nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, "requested resource not found", nil)
+	}
 	return &d.Resp, nil
 }
 
@@ -129,3 +137,180 @@ func (m MockScan) ScanWithContext(aws.Context, *dynamodb.ScanInput, ...request.O
 	}
 	return &m.Resp, nil
 }
+
+// MockedTransactWrite ..
+type MockedTransactWrite struct {
+	dynamodbiface.DynamoDBAPI
+	Resp dynamodb.TransactWriteItemsOutput
+	Err  error
+}
+
+// TransactWriteItemsWithContext mocks dynamo's TransactWriteItemsWithContext
+func (m MockedTransactWrite) TransactWriteItemsWithContext(aws.Context, *dynamodb.TransactWriteItemsInput, ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return &m.Resp, nil
+}
+
+// MockedTransactGet ..
+type MockedTransactGet struct {
+	dynamodbiface.DynamoDBAPI
+	Resp dynamodb.TransactGetItemsOutput
+	Err  error
+}
+
+// TransactGetItemsWithContext mocks dynamo's TransactGetItemsWithContext
+func (m MockedTransactGet) TransactGetItemsWithContext(aws.Context, *dynamodb.TransactGetItemsInput, ...request.Option) (*dynamodb.TransactGetItemsOutput, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return &m.Resp, nil
+}
+
+// MockPagedQuery returns the pages in order, one page per call
+type MockPagedQuery struct {
+	dynamodbiface.DynamoDBAPI
+	Pages []dynamodb.QueryOutput
+	Err   error
+	Calls int
+}
+
+// QueryWithContext mocks QueryWithContext
+func (m *MockPagedQuery) QueryWithContext(aws.Context, *dynamodb.QueryInput, ...request.Option) (*dynamodb.QueryOutput, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.Calls >= len(m.Pages) {
+		return &dynamodb.QueryOutput{}, nil
+	}
+	page := m.Pages[m.Calls]
+	m.Calls++
+	return &page, nil
+}
+
+// MockPagedScan returns the pages in order, one page per call
+type MockPagedScan struct {
+	dynamodbiface.DynamoDBAPI
+	Pages []dynamodb.ScanOutput
+	Err   error
+	Calls int
+}
+
+// ScanWithContext mocks dynamodb's ScanWithContext
+func (m *MockPagedScan) ScanWithContext(aws.Context, *dynamodb.ScanInput, ...request.Option) (*dynamodb.ScanOutput, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.Calls >= len(m.Pages) {
+		return &dynamodb.ScanOutput{}, nil
+	}
+	page := m.Pages[m.Calls]
+	m.Calls++
+	return &page, nil
+}
+
+// MockSegmentedScan returns the pages of every segment in order, it is safe for concurrent use
+type MockSegmentedScan struct {
+	dynamodbiface.DynamoDBAPI
+	Pages map[int64][]dynamodb.ScanOutput
+	Err   error
+	mu    sync.Mutex
+	calls map[int64]int
+}
+
+// ScanWithContext mocks dynamodb's ScanWithContext
+func (m *MockSegmentedScan) ScanWithContext(_ aws.Context, in *dynamodb.ScanInput, _ ...request.Option) (*dynamodb.ScanOutput, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.calls == nil {
+		m.calls = make(map[int64]int)
+	}
+
+	segment := aws.Int64Value(in.Segment)
+	pages := m.Pages[segment]
+	if m.calls[segment] >= len(pages) {
+		return &dynamodb.ScanOutput{}, nil
+	}
+	page := pages[m.calls[segment]]
+	m.calls[segment]++
+	return &page, nil
+}
+
+// MockedRetryBatchWrite reports every written item as unprocessed for the first UnprocessedCalls calls
+// it records the number of calls and the size of the largest chunk, it is safe for concurrent use
+type MockedRetryBatchWrite struct {
+	dynamodbiface.DynamoDBAPI
+	UnprocessedCalls int
+	mu               sync.Mutex
+	Calls            int
+	MaxChunkSize     int
+}
+
+// BatchWriteItemWithContext mocks dynamo's BatchWriteItemWithContext
+func (bw *MockedRetryBatchWrite) BatchWriteItemWithContext(_ aws.Context, in *dynamodb.BatchWriteItemInput, _ ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	bw.Calls++
+	for _, requests := range in.RequestItems {
+		if len(requests) > bw.MaxChunkSize {
+			bw.MaxChunkSize = len(requests)
+		}
+	}
+	if bw.Calls <= bw.UnprocessedCalls {
+		return &dynamodb.BatchWriteItemOutput{UnprocessedItems: in.RequestItems}, nil
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+// MockedRetryBatchGet returns the Items matching the requested keys in the reverse order of the keys,
+// every key is reported as unprocessed for the first UnprocessedCalls calls.
+// it records the number of calls and the size of the largest chunk, it is safe for concurrent use
+type MockedRetryBatchGet struct {
+	dynamodbiface.DynamoDBAPI
+	Items            []map[string]*dynamodb.AttributeValue
+	UnprocessedCalls int
+	mu               sync.Mutex
+	Calls            int
+	MaxChunkSize     int
+}
+
+// BatchGetItemWithContext mocks dynamo's BatchGetItemWithContext
+func (bg *MockedRetryBatchGet) BatchGetItemWithContext(_ aws.Context, in *dynamodb.BatchGetItemInput, _ ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+	bg.Calls++
+	if bg.Calls <= bg.UnprocessedCalls {
+		return &dynamodb.BatchGetItemOutput{UnprocessedKeys: in.RequestItems}, nil
+	}
+
+	out := dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]*dynamodb.AttributeValue{}}
+	for table, keysAndAttributes := range in.RequestItems {
+		if len(keysAndAttributes.Keys) > bg.MaxChunkSize {
+			bg.MaxChunkSize = len(keysAndAttributes.Keys)
+		}
+		for idx := len(keysAndAttributes.Keys) - 1; idx >= 0; idx-- {
+			if item := bg.find(keysAndAttributes.Keys[idx]); item != nil {
+				out.Responses[table] = append(out.Responses[table], item)
+			}
+		}
+	}
+	return &out, nil
+}
+
+// find returns the item having the key attributes, nil if there is none
+func (bg *MockedRetryBatchGet) find(key map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	for _, item := range bg.Items {
+		matched := true
+		for name, value := range key {
+			matched = matched && item[name] != nil && aws.StringValue(item[name].S) == aws.StringValue(value.S)
+		}
+		if matched {
+			return item
+		}
+	}
+	return nil
+}
@@ -1,8 +1,18 @@
 // Package dynamodb ...
 // implements the following functionalities
-// query: GetByID, GetByIDs, GetRecordsWithScanFilter, GetRecordsWithQueryFilter
-// command: AddRecord, UpdateRecordByID, DeleteRecordByID
+// query: GetByID, FindByID, GetByIDs, GetByIDsWithOptions, BatchGetByIDs, GetRecordsWithScanFilter, GetRecordsWithQueryFilter, Query, QueryIterator, ScanIterator, ParallelScan
+// cursor based pagination: GetRecordsWithScanCursor, GetRecordsWithQueryCursor along with WithCursor
+// command: AddRecord, UpdateRecordByID, UpdateByID, UpdateAndReturn, DeleteRecordByID
 // bulk operations: BulkAddRecords, BulkUpdateRecords, BulkDeleteRecords
+// transactions: TransactWrite, TransactGetByIDs
+// typed access: Repository[T] on top of DBHandler
+// multiple tables: DBConfig.Tables routes the calls of the registered model types to their table, see ForModel
+// single table design: DBTableInfo.TypeAttribute stores the model type, filters the queries and rejects other model types
+// composite keys: CompositeKeys and KeyTemplate build and parse keys eg. USER#123, queried by prefix using WithKeyPrefix
+// polymorphic queries: QueryModels unmarshals the records using the ModelRegistry, see GroupRecords and RecordsOf
+// struct tag driven models: Model[T] along with RegisterModel, or generated with cmd/dyorm-gen
+// optimistic locking: VersionedModel or the dyorm version tag, conflicts are returned as ErrVersionConflict
+// errors: ErrNotFound, ErrConditionFailed, ErrThrottled, ErrValidation, ErrItemTooLarge, ErrTransactionCanceled
 // for bulk operations and get all there is some AWS dynamo limits regarding the number of records and size
 // please refer to aws documentation
 //
@@ -110,15 +120,10 @@
 //		if lastItemID != "" {
 //			filter.WithLastEvaluatedKey("user_id", lastItemID, nil, nil)
 //		}
-//		items, err := h.db.GetRecordsWithScanFilter(ctx, User{}, filter)
-//		if err != nil {
-//			// you should handle the error, as for what is written to show the usage
-//			ch <- User{}
-//			return
-//		}
-//
-//		for _, item := range items {
-//			user, ok := item.(User)
+//		// the iterator follows the LastEvaluatedKey and fetches the next page on demand
+//		it := h.db.ScanIterator(ctx, User{}, filter)
+//		for it.Next() {
+//			user, ok := it.Item().(User)
 //			if !ok {
 //				// you should handle the error, as for what is written to show the usage
 //				ch <- User{}
@@ -127,6 +132,10 @@
 //			// everything is fine
 //			ch <- user
 //		}
+//		if it.Err() != nil {
+//			// you should handle the error, as for what is written to show the usage
+//			ch <- User{}
+//		}
 //	}
 //	func (h dbHandler) getAllByScan(ctx context.Context, pageSize int, lastItemID string, ch chan<- User) {
 //		// please note that this is a query which is efficient in terms of cost and time
@@ -142,15 +151,10 @@
 //		if lastItemID != "" {
 //			filter.WithLastEvaluatedKey("user_id", lastItemID, nil, nil)
 //		}
-//		items, err := h.db.GetRecordsWithQueryFilter(ctx, User{}, filter)
-//		if err != nil {
-//			// you should handle the error, as for what is written to show the usage
-//			ch <- User{}
-//			return
-//		}
-//
-//		for _, item := range items {
-//			user, ok := item.(User)
+//		// the iterator follows the LastEvaluatedKey and fetches the next page on demand
+//		it := h.db.QueryIterator(ctx, User{}, filter)
+//		for it.Next() {
+//			user, ok := it.Item().(User)
 //			if !ok {
 //				// you should handle the error, as for what is written to show the usage
 //				ch <- User{}
@@ -159,6 +163,10 @@
 //			// everything is fine
 //			ch <- user
 //		}
+//		if it.Err() != nil {
+//			// you should handle the error, as for what is written to show the usage
+//			ch <- User{}
+//		}
 //	}
 //
 // main entry point initialize dynamo config along with the repository
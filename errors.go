@@ -0,0 +1,150 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// the error kinds returned by the handler, the errors can be matched using errors.Is
+// while the underlying dynamodb error is still available using errors.As eg. awserr.Error
+var (
+	// ErrNotFound the record does not exist
+	ErrNotFound = errors.New("record not found")
+	// ErrConditionFailed the condition of a write was not matched
+	ErrConditionFailed = errors.New("condition failed")
+	// ErrThrottled the request exceeded the provisioned throughput or the account limits
+	ErrThrottled = errors.New("request throttled")
+	// ErrValidation the request is invalid eg. a missing key or an invalid condition, see ValidationError
+	ErrValidation = errors.New("validation failed")
+	// ErrItemTooLarge the item exceeds the dynamodb item or item collection size limit
+	ErrItemTooLarge = errors.New("item too large")
+	// ErrTransactionCanceled the transaction was canceled, see TransactionCanceledError
+	ErrTransactionCanceled = errors.New("transaction canceled")
+)
+
+// the field names of the key validation errors
+const (
+	partitionKeyField = "partition key"
+	sortKeyField      = "sort key"
+	tableNameField    = "table name"
+)
+
+// ValidationError is returned for invalid requests, it matches ErrValidation
+// Field names the invalid input eg. partition key, sort key or the attribute name of a condition
+type ValidationError struct {
+	Field   string
+	Message string
+	err     error
+}
+
+// Error returns the validation message
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Is matches ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Unwrap returns the underlying error if any
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+// NotFoundError is returned by the strict reads when the record does not exist, it matches ErrNotFound
+type NotFoundError struct {
+	Table string
+	Keys  DBPSKeyValues
+}
+
+// Error returns the error message along with the record keys
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("record not found in %s: %s", e.Table, e.Keys.GetPartitionKey())
+	if sortKey := e.Keys.GetSortKey(); sortKey != nil {
+		msg += ", " + string(*sortKey)
+	}
+	return msg
+}
+
+// Is matches ErrNotFound
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// newValidationErr creates a validation error of the field
+func newValidationErr(field, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
+
+// validationErr wraps the error as a validation error of the field, nil is returned as is
+func validationErr(field string, err error) error {
+	var valErr *ValidationError
+	if err == nil || errors.As(err, &valErr) {
+		return err
+	}
+	return &ValidationError{Field: field, Message: err.Error(), err: err}
+}
+
+// dbError wraps a dynamodb error along with its kind, the message of the dynamodb error is kept as is
+type dbError struct {
+	kind error
+	err  error
+}
+
+// Error returns the message of the dynamodb error
+func (e *dbError) Error() string {
+	return e.err.Error()
+}
+
+// Is matches the error kind
+func (e *dbError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Unwrap returns the dynamodb error
+func (e *dbError) Unwrap() error {
+	return e.err
+}
+
+// translateErr wraps the dynamodb errors with the matching error kind, other errors are returned as is
+func translateErr(err error) error {
+	var awsErr awserr.Error
+	if err == nil || !errors.As(err, &awsErr) {
+		return err
+	}
+	// already translated
+	var dbErr *dbError
+	var valErr *ValidationError
+	var canceledErr *TransactionCanceledError
+	if errors.As(err, &dbErr) || errors.As(err, &valErr) || errors.As(err, &canceledErr) {
+		return err
+	}
+
+	switch awsErr.Code() {
+	case dynamodb.ErrCodeConditionalCheckFailedException:
+		return &dbError{kind: ErrConditionFailed, err: err}
+	case dynamodb.ErrCodeProvisionedThroughputExceededException, dynamodb.ErrCodeRequestLimitExceeded,
+		"ThrottlingException":
+		return &dbError{kind: ErrThrottled, err: err}
+	case dynamodb.ErrCodeItemCollectionSizeLimitExceededException:
+		return &dbError{kind: ErrItemTooLarge, err: err}
+	case dynamodb.ErrCodeTransactionCanceledException:
+		var canceled *dynamodb.TransactionCanceledException
+		if errors.As(err, &canceled) {
+			return decodeTransactionErr(err)
+		}
+		return &dbError{kind: ErrTransactionCanceled, err: err}
+	case "ValidationException":
+		// eg. Item size has exceeded the maximum allowed size
+		if strings.Contains(awsErr.Message(), "Item size") {
+			return &dbError{kind: ErrItemTooLarge, err: err}
+		}
+		return &ValidationError{Message: err.Error(), err: err}
+	}
+	return err
+}
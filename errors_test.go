@@ -0,0 +1,128 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateErr(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{
+			name: "condition failed",
+			err:  awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil),
+			kind: ErrConditionFailed,
+		},
+		{
+			name: "provisioned throughput exceeded",
+			err:  awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "throughput exceeded", nil),
+			kind: ErrThrottled,
+		},
+		{
+			name: "request limit exceeded",
+			err:  awserr.New(dynamodb.ErrCodeRequestLimitExceeded, "limit exceeded", nil),
+			kind: ErrThrottled,
+		},
+		{
+			name: "item collection too large",
+			err:  awserr.New(dynamodb.ErrCodeItemCollectionSizeLimitExceededException, "collection too large", nil),
+			kind: ErrItemTooLarge,
+		},
+		{
+			name: "item too large",
+			err:  awserr.New("ValidationException", "Item size has exceeded the maximum allowed size", nil),
+			kind: ErrItemTooLarge,
+		},
+		{
+			name: "validation",
+			err:  awserr.New("ValidationException", "The provided key element does not match the schema", nil),
+			kind: ErrValidation,
+		},
+		{
+			name: "transaction canceled",
+			err:  awserr.New(dynamodb.ErrCodeTransactionCanceledException, "canceled", nil),
+			kind: ErrTransactionCanceled,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := translateErr(tc.err)
+			assert.True(t, errors.Is(err, tc.kind))
+			assert.Equal(t, tc.err.Error(), err.Error())
+			// the dynamodb error is still available
+			var awsErr awserr.Error
+			assert.True(t, errors.As(err, &awsErr))
+			assert.Equal(t, err, translateErr(err))
+		})
+	}
+
+	t.Run("other errors", func(t *testing.T) {
+		assert.Nil(t, translateErr(nil))
+		err := errors.New("db error")
+		assert.Equal(t, err, translateErr(err))
+		err = awserr.New(dynamodb.ErrCodeInternalServerError, "internal error", nil)
+		assert.Equal(t, err, translateErr(err))
+	})
+}
+
+func TestValidationError(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing keys", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockDeleteItem{}}
+		err := repo.DeleteRecordByID(ctx, dbPSKeyValues{partitionKey: "part"}, nil)
+		assert.True(t, errors.Is(err, ErrValidation))
+		assert.EqualError(t, err, "missing required sort key")
+
+		var valErr *ValidationError
+		assert.True(t, errors.As(err, &valErr))
+		assert.Equal(t, "sort key", valErr.Field)
+
+		_, err = repo.GetByID(ctx, TestBaseModel{}, "", dbPSKeyValues{})
+		assert.True(t, errors.As(err, &valErr))
+		assert.Equal(t, "partition key", valErr.Field)
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+		_, err := NewExpressionWrapper("table").
+			WithCondition("tags", "a", SIZE).
+			BuildScanInput()
+		var valErr *ValidationError
+		assert.True(t, errors.As(err, &valErr))
+		assert.Equal(t, "tags", valErr.Field)
+
+		err = Cond.Where("name", 1, BEGINSWITH).Err()
+		assert.True(t, errors.As(err, &valErr))
+		assert.Equal(t, "name", valErr.Field)
+	})
+
+	t.Run("missing table name", func(t *testing.T) {
+		_, err := NewExpressionWrapper("").BuildGetInput()
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+}
+
+func TestHandlerImp_TranslatedErrors(t *testing.T) {
+	ctx := context.Background()
+	throttled := awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "throughput exceeded", nil)
+
+	repo := handlerImp{config: cfg, DynamoDBAPI: MockedPutItem{Err: awserr.New(
+		dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil,
+	)}}
+	_, err := repo.AddRecord(ctx, TestBaseModel{Name: "golang", SKey: "key"}, true)
+	assert.True(t, errors.Is(err, ErrConditionFailed))
+
+	repo = handlerImp{config: cfg, DynamoDBAPI: MockedGetItem{Err: throttled}}
+	sortKey := DBKeyValue("key")
+	_, err = repo.GetByID(ctx, TestBaseModel{}, "", dbPSKeyValues{partitionKey: "part", sortKey: &sortKey})
+	assert.True(t, errors.Is(err, ErrThrottled))
+}
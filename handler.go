@@ -3,6 +3,7 @@ package dynamodb
 import (
 	"context"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
@@ -10,8 +11,23 @@ import (
 )
 
 // DBKeyValue a type for partition or sort key
+// number keys hold the decimal representation and binary keys the raw bytes, see DBKeyType
 type DBKeyValue string
 
+// keyValueOf converts a key attribute value of any scalar type back to DBKeyValue
+func keyValueOf(value *dynamodb.AttributeValue) DBKeyValue {
+	switch {
+	case value == nil:
+		return ""
+	case value.N != nil:
+		return DBKeyValue(*value.N)
+	case value.B != nil:
+		return DBKeyValue(value.B)
+	default:
+		return DBKeyValue(aws.StringValue(value.S))
+	}
+}
+
 // DBPSKeyValues holds the partition and the sort keys
 type DBPSKeyValues interface {
 	GetPartitionKey() DBKeyValue
@@ -48,13 +64,36 @@ func (d dbPSKeyValues) GetSortKey() *DBKeyValue {
 type DBQueries interface {
 	// GetByID get by partition (& sort) key(s)
 	GetByID(ctx context.Context, input BaseModel, name DynamoTableOrIndexName, dbKeys DBPSKeyValues) (BaseModel, error)
+	// FindByID get by partition (& sort) key(s) using the read options, returns ErrNotFound if the record does not exist
+	FindByID(ctx context.Context, input BaseModel, name DynamoTableOrIndexName, dbKeys DBPSKeyValues, options *AwsExpressionWrapper) (BaseModel, error)
 	// GetByIDs get records by their partition (& sort) keys
 	GetByIDs(ctx context.Context, input BaseModel, dbKeys []DBPSKeyValues) ([]BaseModel, error)
+	// GetByIDsWithOptions get records by their partition (& sort) keys using the read options eg. WithConsistentRead
+	GetByIDsWithOptions(ctx context.Context, input BaseModel, dbKeys []DBPSKeyValues, options *AwsExpressionWrapper) ([]BaseModel, error)
+	// BatchGetByIDs get records by their partition (& sort) keys from the table of the options, in the order of the keys,
+	// along with the missing, invalid and unprocessed keys
+	BatchGetByIDs(ctx context.Context, input BaseModel, dbKeys []DBPSKeyValues, options *AwsExpressionWrapper) (BatchGetResult, error)
 	// GetRecordsWithScanFilter gets all records that match the provided filter using scan req
 	// @TODO change it to map[string]interface{}
 	GetRecordsWithScanFilter(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, DBAttributeValues, error)
 	// GetRecordsWithQueryFilter gets all records that match the provided filter using query req
 	GetRecordsWithQueryFilter(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, DBAttributeValues, error)
+	// Query gets a page of records of the partition of the table or index, the key names are taken from the config
+	Query(ctx context.Context, input BaseModel, index DynamoTableOrIndexName, partitionValue DBKeyValue, sortKey *SortKeyCondition) ([]BaseModel, DBAttributeValues, error)
+	// QueryModels gets a page of records using query req, unmarshalling every record using the model registered for its type
+	QueryModels(ctx context.Context, registry ModelRegistry, filters *AwsExpressionWrapper) ([]BaseModel, DBAttributeValues, error)
+	// GetRecordsWithScanCursor gets a page of records using scan req along with an opaque cursor for the next page
+	GetRecordsWithScanCursor(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, string, error)
+	// GetRecordsWithQueryCursor gets a page of records using query req along with an opaque cursor for the next page
+	GetRecordsWithQueryCursor(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, string, error)
+	// QueryIterator iterates over all the records matching the query filters, fetching pages on demand
+	QueryIterator(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) *RecordIterator
+	// ScanIterator iterates over all the records matching the scan filters, fetching pages on demand
+	ScanIterator(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) *RecordIterator
+	// ParallelScan scans the table using concurrent segments, passing the records page by page to fn
+	ParallelScan(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper, totalSegments int64, resume []ScanSegment, fn ScanPageFunc) error
+	// TransactGetByIDs gets records (maximum 100 item) with snapshot consistency using a single transaction
+	TransactGetByIDs(ctx context.Context, items ...TransactGetItem) ([]BaseModel, error)
 }
 
 // DBCommands DynamoDB commands related interface
@@ -63,13 +102,18 @@ type DBCommands interface {
 	AddRecord(ctx context.Context, in BaseModel, createSortKey bool) (DBPSKeyValues, error)
 	// UpdateRecordByID updates a dynamodb record
 	UpdateRecordByID(ctx context.Context, in BaseModel, dbKeys DBPSKeyValues) error
+	// UpdateByID partially updates a dynamodb record using the update expression of the wrapper
+	UpdateByID(ctx context.Context, dbKeys DBPSKeyValues, update *AwsExpressionWrapper) error
+	// UpdateAndReturn partially updates a dynamodb record and returns its attributes unmarshalled using input
+	UpdateAndReturn(ctx context.Context, input BaseModel, dbKeys DBPSKeyValues, update *AwsExpressionWrapper) (BaseModel, error)
 	// DeleteRecordByID deletes a dynamodb record if the passed filters were matched:
 	DeleteRecordByID(ctx context.Context, dbKeys DBPSKeyValues, filters *AwsExpressionWrapper) error
 }
 
 // DBBulkCommands Dynamo Bulk commands related interface
 type DBBulkCommands interface {
-	// BulkAddRecords inserts a bulk of records (maximum 25 item at a time) into dynamodb table
+	// BulkAddRecords inserts a bulk of records into dynamodb table, written in chunks of 25 items
+	// the unprocessed items are retried until the deadline defined in DBConfig.BatchWrite and only the failed ones are returned
 	BulkAddRecords(ctx context.Context, baseModel BaseModel, createSortKey bool, records ...BaseModel) ([]BaseModel, error)
 	// BulkUpdateRecords updates multiple dynamo records
 	BulkUpdateRecords(ctx context.Context, baseModel BaseModel, records ...BaseModel) ([]BaseModel, error)
@@ -77,11 +121,21 @@ type DBBulkCommands interface {
 	BulkDeleteRecords(ctx context.Context, dbKeys ...DBPSKeyValues) ([]DBPSKeyValues, error)
 }
 
+// DBTransactions DynamoDB transactions related interface
+type DBTransactions interface {
+	// TransactWrite executes put, update, delete and condition check operations atomically (maximum 100 item)
+	// the request token makes the call idempotent, a new token is generated if it is empty
+	TransactWrite(ctx context.Context, requestToken string, items ...TransactWriteItem) error
+}
+
 // DBHandler DynamoDB interface
 type DBHandler interface {
 	DBQueries
 	DBCommands
 	DBBulkCommands
+	DBTransactions
+	// ForModel returns a handler bound to the table of the model type eg. for BulkDeleteRecords, which takes only keys
+	ForModel(modelType DBModelName) DBHandler
 }
 
 type handlerImp struct {
@@ -104,3 +158,31 @@ func NewDynamoDB(cfg DBConfig) (DBHandler, error) {
 	client := dynamodb.New(sess)
 	return &handlerImp{config: cfg, DynamoDBAPI: client}, nil
 }
+
+// ForModel returns a handler bound to the table of the model type, the main table is used if the model is not registered
+func (h handlerImp) ForModel(modelType DBModelName) DBHandler {
+	if table, ok := h.config.modelTable(modelType); ok {
+		h.config = h.config.withTable(table)
+	}
+	return h
+}
+
+// route returns the handler bound to the table of the call: the table named by the expression if it is registered,
+// otherwise the table of the model type, the main table is used by default
+func (h handlerImp) route(model BaseModel, expr *AwsExpressionWrapper) handlerImp {
+	if expr != nil && expr.dynamoDBTable != "" {
+		if expr.dynamoDBTable == h.config.TableInfo.TableName {
+			return h
+		}
+		if table, ok := h.config.namedTable(expr.dynamoDBTable); ok {
+			h.config = h.config.withTable(table)
+			return h
+		}
+	}
+	if model != nil {
+		if table, ok := h.config.modelTable(model.GetModelType()); ok {
+			h.config = h.config.withTable(table)
+		}
+	}
+	return h
+}
@@ -0,0 +1,125 @@
+package dynamodb
+
+import (
+	"context"
+)
+
+// pageFetcher fetches a single page of records for the provided filters
+type pageFetcher func(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, DBAttributeValues, error)
+
+// RecordIterator lazily iterates over all the records of a query or a scan
+// pages are fetched on demand following the LastEvaluatedKey returned by dynamodb
+//
+//	it := db.QueryIterator(ctx, User{}, filters).WithMaxItems(100)
+//	for it.Next() {
+//		user := it.Item().(User)
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle the error
+//	}
+type RecordIterator struct {
+	ctx      context.Context
+	input    BaseModel
+	filters  *AwsExpressionWrapper
+	fetch    pageFetcher
+	page     []BaseModel
+	pageIdx  int
+	item     BaseModel
+	lastKey  DBAttributeValues
+	started  bool
+	maxItems int
+	count    int
+	err      error
+}
+
+// QueryIterator returns an iterator over all the records matching the query filters
+func (h handlerImp) QueryIterator(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) *RecordIterator {
+	return newRecordIterator(ctx, input, filters, h.GetRecordsWithQueryFilter)
+}
+
+// ScanIterator returns an iterator over all the records matching the scan filters
+func (h handlerImp) ScanIterator(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) *RecordIterator {
+	return newRecordIterator(ctx, input, filters, h.GetRecordsWithScanFilter)
+}
+
+func newRecordIterator(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper, fetch pageFetcher) *RecordIterator {
+	it := &RecordIterator{
+		ctx:   ctx,
+		input: input,
+		fetch: fetch,
+	}
+	if filters != nil {
+		// copy the filters as the exclusive start key is changed on every page
+		copied := *filters
+		it.filters = &copied
+		it.lastKey = copied.exclusiveStartKey
+	}
+	return it
+}
+
+// WithMaxItems caps the total number of records returned by the iterator
+// regardless of the page size defined with WithLimit
+func (it *RecordIterator) WithMaxItems(maxItems int) *RecordIterator {
+	it.maxItems = maxItems
+	return it
+}
+
+// Next advances the iterator to the next record, fetching the next page if needed
+// returns false when there are no more records, the max items is reached or an error occurred
+func (it *RecordIterator) Next() bool {
+	it.item = nil
+	if it.err != nil || (it.maxItems > 0 && it.count >= it.maxItems) {
+		return false
+	}
+
+	for it.pageIdx >= len(it.page) {
+		if it.started && len(it.lastKey) == 0 {
+			return false
+		}
+		if !it.loadPage() {
+			return false
+		}
+	}
+
+	it.item = it.page[it.pageIdx]
+	it.pageIdx++
+	it.count++
+	return true
+}
+
+// Item returns the current record
+func (it *RecordIterator) Item() BaseModel {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration if any
+func (it *RecordIterator) Err() error {
+	return it.err
+}
+
+// loadPage fetches the next page starting from the last evaluated key
+func (it *RecordIterator) loadPage() bool {
+	if it.filters == nil {
+		it.err = newValidationErr("filters", "missing query or scan filters")
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	it.filters.WithExlusiveStartingKey(it.lastKey)
+	page, lastKey, err := it.fetch(it.ctx, it.input, it.filters)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	// the cursor defines the starting point of the first page only
+	it.filters.cursor = ""
+	it.started = true
+	it.page = page
+	it.pageIdx = 0
+	it.lastKey = lastKey
+	return true
+}
@@ -0,0 +1,144 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func createIteratorPageItems(names ...string) []map[string]*dynamodb.AttributeValue {
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(names))
+	for idx, name := range names {
+		items = append(items, map[string]*dynamodb.AttributeValue{
+			"Name": {S: aws.String(name)},
+			"Age":  {N: aws.String(strconv.Itoa(idx + 1))},
+		})
+	}
+	return items
+}
+
+func TestHandlerImp_QueryIterator(t *testing.T) {
+	lastKey := map[string]*dynamodb.AttributeValue{
+		"name": {S: aws.String("b")},
+	}
+	pages := []dynamodb.QueryOutput{
+		{Items: createIteratorPageItems("a", "b"), LastEvaluatedKey: lastKey},
+		{Items: createIteratorPageItems("c")},
+	}
+
+	t.Run("successfully iterates over all pages", func(t *testing.T) {
+		mock := &MockPagedQuery{Pages: pages}
+		repo := handlerImp{config: cfg, DynamoDBAPI: mock}
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithKeyCondition("pName", "pValue", EQUAL).
+			WithLimit(2)
+
+		it := repo.QueryIterator(context.Background(), TestBaseModel{}, filters)
+		names := make([]string, 0)
+		for it.Next() {
+			names = append(names, it.Item().(TestBaseModel).Name)
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []string{"a", "b", "c"}, names)
+		assert.Equal(t, 2, mock.Calls)
+		// the original filters are not changed by the iterator
+		assert.Empty(t, filters.exclusiveStartKey)
+	})
+
+	t.Run("stops at max items", func(t *testing.T) {
+		mock := &MockPagedQuery{Pages: pages}
+		repo := handlerImp{config: cfg, DynamoDBAPI: mock}
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithKeyCondition("pName", "pValue", EQUAL)
+
+		it := repo.QueryIterator(context.Background(), TestBaseModel{}, filters).WithMaxItems(1)
+		count := 0
+		for it.Next() {
+			count++
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, 1, count)
+		assert.Equal(t, 1, mock.Calls)
+		assert.Nil(t, it.Item())
+	})
+
+	t.Run("with db error", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: &MockPagedQuery{Err: errors.New("db error")}}
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithKeyCondition("pName", "pValue", EQUAL)
+
+		it := repo.QueryIterator(context.Background(), TestBaseModel{}, filters)
+		assert.False(t, it.Next())
+		assert.Error(t, it.Err())
+	})
+
+	t.Run("with canceled context", func(t *testing.T) {
+		mock := &MockPagedQuery{Pages: pages}
+		repo := handlerImp{config: cfg, DynamoDBAPI: mock}
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithKeyCondition("pName", "pValue", EQUAL)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		it := repo.QueryIterator(ctx, TestBaseModel{}, filters)
+		assert.True(t, it.Next())
+		assert.True(t, it.Next())
+		cancel()
+		assert.False(t, it.Next())
+		assert.ErrorIs(t, it.Err(), context.Canceled)
+		assert.Equal(t, 1, mock.Calls)
+	})
+
+	t.Run("without filters", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: &MockPagedQuery{Pages: pages}}
+		it := repo.QueryIterator(context.Background(), TestBaseModel{}, nil)
+		assert.False(t, it.Next())
+		assert.ErrorIs(t, it.Err(), ErrValidation)
+	})
+}
+
+func TestHandlerImp_ScanIterator(t *testing.T) {
+	lastKey := map[string]*dynamodb.AttributeValue{
+		"name": {S: aws.String("a")},
+	}
+
+	t.Run("successfully iterates over all pages", func(t *testing.T) {
+		mock := &MockPagedScan{
+			Pages: []dynamodb.ScanOutput{
+				{Items: createIteratorPageItems("a"), LastEvaluatedKey: lastKey},
+				{Items: createIteratorPageItems(), LastEvaluatedKey: lastKey},
+				{Items: createIteratorPageItems("b")},
+			},
+		}
+		repo := handlerImp{config: cfg, DynamoDBAPI: mock}
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName).
+			WithCondition("Age", 1, GE)
+
+		it := repo.ScanIterator(context.Background(), TestBaseModel{}, filters)
+		names := make([]string, 0)
+		for it.Next() {
+			names = append(names, it.Item().(TestBaseModel).Name)
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []string{"a", "b"}, names)
+		assert.Equal(t, 3, mock.Calls)
+	})
+
+	t.Run("with unmarshalling error", func(t *testing.T) {
+		mock := &MockPagedScan{
+			Pages: []dynamodb.ScanOutput{
+				{Items: createIteratorPageItems("a")},
+			},
+		}
+		repo := handlerImp{config: cfg, DynamoDBAPI: mock}
+		filters := NewExpressionWrapper(cfg.TableInfo.TableName)
+
+		it := repo.ScanIterator(context.Background(), TestBaseModel{withMarshallingErr: true}, filters)
+		assert.False(t, it.Next())
+		assert.Error(t, it.Err())
+	})
+}
@@ -0,0 +1,93 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+)
+
+// SortKeyCondition the condition on the sort key of a key query eg. SortKeyCondition{Operator: BEGINSWITH, Value: "ORDER#"}
+// the DBKeyValue values, including the Range bounds, are converted to the sort key type, the other values are marshalled as is
+type SortKeyCondition struct {
+	Operator Operator
+	Value    interface{}
+}
+
+// sortKeyOperators the operators supported by dynamodb on the sort keys
+var sortKeyOperators = map[Operator]bool{
+	EQUAL:      true,
+	LT:         true,
+	LE:         true,
+	GT:         true,
+	GE:         true,
+	BETWEEN:    true,
+	BEGINSWITH: true,
+}
+
+// KeyQuery builds the query filters matching the partition of the table, or of the index if provided,
+// and the optional sort key condition, the key names and types are taken from the config.
+// further options eg. WithLimit or WithCondition can be added to the returned filters
+func (c DBConfig) KeyQuery(
+	index DynamoTableOrIndexName, partitionValue DBKeyValue, sortKey *SortKeyCondition,
+) (*AwsExpressionWrapper, error) {
+	keyNames := c.TableInfo.DBPSKeyNames
+	if index != "" {
+		keys, ok := c.Indexes[index]
+		if !ok {
+			return nil, newValidationErr("index", fmt.Sprintf("unknown index %s", index))
+		}
+		keyNames = keys
+	}
+	if len(partitionValue) < 1 {
+		return nil, newValidationErr(partitionKeyField, "missing partition key value")
+	}
+
+	filters := NewExpressionWrapper(c.TableInfo.TableName).
+		WithIndexName(string(index)).
+		WithKeyCondition(string(keyNames.PartitionKey), keyNames.PartitionKeyType.AttributeValue(partitionValue), EQUAL)
+	if sortKey == nil {
+		return filters, filters.err
+	}
+
+	if keyNames.SortKey == nil {
+		return nil, newValidationErr(sortKeyField, "the keys have no sort key")
+	}
+	if !sortKeyOperators[sortKey.Operator] {
+		return nil, newValidationErr(sortKeyField, fmt.Sprintf("operator %v is not supported on sort keys", sortKey.Operator))
+	}
+	if sortKey.Operator == BEGINSWITH && !isStringKey(keyNames.SortKeyType) {
+		return nil, newValidationErr(sortKeyField, "BEGINSWITH is supported on string sort keys only")
+	}
+
+	filters.AndKeyCondition(string(*keyNames.SortKey), sortKeyValue(keyNames.SortKeyType, sortKey), sortKey.Operator)
+	return filters, filters.err
+}
+
+// sortKeyValue converts the DBKeyValue values of the condition to the sort key type
+func sortKeyValue(keyType DBKeyType, condition *SortKeyCondition) interface{} {
+	if condition.Operator == BEGINSWITH {
+		return condition.Value
+	}
+	convert := func(value interface{}) interface{} {
+		if keyValue, ok := value.(DBKeyValue); ok {
+			return keyType.AttributeValue(keyValue)
+		}
+		return value
+	}
+	if bounds, ok := condition.Value.(Range); ok {
+		return Range{From: convert(bounds.From), To: convert(bounds.To)}
+	}
+	return convert(condition.Value)
+}
+
+// Query gets a page of records of the partition of the table, or of the index if provided, optionally matching
+// the sort key condition, the key names are taken from the config of the model table, see KeyQuery
+func (h handlerImp) Query(
+	ctx context.Context, input BaseModel, index DynamoTableOrIndexName, partitionValue DBKeyValue, sortKey *SortKeyCondition,
+) ([]BaseModel, DBAttributeValues, error) {
+	h = h.route(input, nil)
+	filters, err := h.config.KeyQuery(index, partitionValue, sortKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.GetRecordsWithQueryFilter(ctx, input, filters)
+}
@@ -0,0 +1,148 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBConfig_KeyQuery(t *testing.T) {
+	seq := DBKeyName("seq")
+	config := cfg
+	config.Indexes = map[DynamoTableOrIndexName]DBPSKeyNames{
+		"by_email": {PartitionKey: "email"},
+		"by_seq":   {PartitionKey: "user_id", SortKey: &seq, SortKeyType: KeyTypeNumber},
+	}
+
+	cases := []struct {
+		name          string
+		index         DynamoTableOrIndexName
+		partition     DBKeyValue
+		sortKey       *SortKeyCondition
+		keyCondition  string
+		values        map[string]*dynamodb.AttributeValue
+		expectedIndex *string
+		hasError      bool
+	}{
+		{
+			name:         "table partition",
+			partition:    "golang",
+			keyCondition: "#0 = :0",
+			values:       map[string]*dynamodb.AttributeValue{":0": {S: aws.String("golang")}},
+		},
+		{
+			name:         "table sort key prefix",
+			partition:    "golang",
+			sortKey:      &SortKeyCondition{Operator: BEGINSWITH, Value: "ORDER#"},
+			keyCondition: "(#0 = :0) AND (begins_with (#1, :1))",
+			values: map[string]*dynamodb.AttributeValue{
+				":0": {S: aws.String("golang")},
+				":1": {S: aws.String("ORDER#")},
+			},
+		},
+		{
+			name:          "index number sort key range",
+			index:         "by_seq",
+			partition:     "1",
+			sortKey:       &SortKeyCondition{Operator: BETWEEN, Value: Range{From: DBKeyValue("3"), To: DBKeyValue("7")}},
+			keyCondition:  "(#0 = :0) AND (#1 BETWEEN :1 AND :2)",
+			expectedIndex: aws.String("by_seq"),
+			values: map[string]*dynamodb.AttributeValue{
+				":0": {S: aws.String("1")},
+				":1": {N: aws.String("3")},
+				":2": {N: aws.String("7")},
+			},
+		},
+		{
+			name:          "index partition",
+			index:         "by_email",
+			partition:     "user@mail.com",
+			keyCondition:  "#0 = :0",
+			expectedIndex: aws.String("by_email"),
+			values:        map[string]*dynamodb.AttributeValue{":0": {S: aws.String("user@mail.com")}},
+		},
+		{name: "unknown index", index: "unknown", partition: "golang", hasError: true},
+		{name: "missing partition value", hasError: true},
+		{
+			name:      "unsupported operator",
+			partition: "golang",
+			sortKey:   &SortKeyCondition{Operator: NE, Value: "a"},
+			hasError:  true,
+		},
+		{
+			name:      "index without sort key",
+			index:     "by_email",
+			partition: "user@mail.com",
+			sortKey:   &SortKeyCondition{Operator: EQUAL, Value: "a"},
+			hasError:  true,
+		},
+		{
+			name:      "prefix of number sort key",
+			index:     "by_seq",
+			partition: "1",
+			sortKey:   &SortKeyCondition{Operator: BEGINSWITH, Value: "1"},
+			hasError:  true,
+		},
+		{
+			name:      "invalid range",
+			partition: "golang",
+			sortKey:   &SortKeyCondition{Operator: BETWEEN, Value: "a"},
+			hasError:  true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			filters, err := config.KeyQuery(tc.index, tc.partition, tc.sortKey)
+			if tc.hasError {
+				assert.True(t, errors.Is(err, ErrValidation), err)
+				return
+			}
+			assert.NoError(t, err)
+
+			query, err := filters.BuildQueryInput()
+			assert.NoError(t, err)
+			assert.Equal(t, "table", aws.StringValue(query.TableName))
+			assert.Equal(t, tc.expectedIndex, query.IndexName)
+			assert.Equal(t, tc.keyCondition, aws.StringValue(query.KeyConditionExpression))
+			assert.Equal(t, tc.values, query.ExpressionAttributeValues)
+		})
+	}
+}
+
+func TestHandlerImp_Query(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successfully", func(t *testing.T) {
+		repo := handlerImp{
+			config:      cfg,
+			DynamoDBAPI: MockQuery{Resp: dynamodb.QueryOutput{Items: createIteratorPageItems("golang")}},
+		}
+		records, _, err := repo.Query(ctx, TestBaseModel{}, "", "golang", &SortKeyCondition{Operator: GE, Value: "a"})
+		assert.NoError(t, err)
+		assert.Len(t, records, 1)
+	})
+
+	t.Run("with invalid sort key condition", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockQuery{}}
+		_, _, err := repo.Query(ctx, TestBaseModel{}, "", "golang", &SortKeyCondition{Operator: CONTAINS, Value: "a"})
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+
+	t.Run("uses the keys of the model table", func(t *testing.T) {
+		config := cfg
+		config.Tables = []DBTableConfig{ordersTable}
+		repo := handlerImp{config: config, DynamoDBAPI: MockQuery{}}
+
+		// the orders table has no sort key
+		_, _, err := repo.Query(ctx, orderModel{}, "", "1", &SortKeyCondition{Operator: EQUAL, Value: "a"})
+		assert.True(t, errors.Is(err, ErrValidation))
+
+		_, _, err = repo.Query(ctx, orderModel{}, "by_user", "123", nil)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,109 @@
+package dynamodb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// ModelRegistry maps the model types to the models used to unmarshal their records,
+// it decodes the item collections of single table designs where a query returns records of several model types
+type ModelRegistry map[DBModelName]BaseModel
+
+// NewModelRegistry registers the models under their model type
+func NewModelRegistry(models ...BaseModel) ModelRegistry {
+	registry := make(ModelRegistry, len(models))
+	for _, mdl := range models {
+		registry[mdl.GetModelType()] = mdl
+	}
+	return registry
+}
+
+// modelTypes returns the sorted model types of the registry
+func (r ModelRegistry) modelTypes() []string {
+	types := make([]string, 0, len(r))
+	for modelType := range r {
+		types = append(types, string(modelType))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// GroupedRecords the records grouped by their model type
+type GroupedRecords map[DBModelName][]BaseModel
+
+// GroupRecords groups the records by their model type keeping their order
+func GroupRecords(records []BaseModel) GroupedRecords {
+	grouped := make(GroupedRecords)
+	for _, rec := range records {
+		grouped[rec.GetModelType()] = append(grouped[rec.GetModelType()], rec)
+	}
+	return grouped
+}
+
+// RecordsOf returns the grouped records of the model type of T, the records which are not a T are skipped
+func RecordsOf[T BaseModel](grouped GroupedRecords) []T {
+	var model T
+	records := make([]T, 0, len(grouped[model.GetModelType()]))
+	for _, rec := range grouped[model.GetModelType()] {
+		if record, ok := rec.(T); ok {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// QueryModels gets a page of records that match the provided filter using query req, every record is unmarshalled
+// using the registered model of its type attribute (see DBTableInfo.TypeAttribute), the query is filtered
+// by the registered model types, returns a ModelTypeError for the records of other types
+func (h handlerImp) QueryModels(
+	ctx context.Context, registry ModelRegistry, filters *AwsExpressionWrapper,
+) ([]BaseModel, DBAttributeValues, error) {
+	if filters == nil {
+		return nil, nil, newValidationErr("filters", "missing query filters")
+	}
+	h = h.route(nil, filters)
+	attribute := h.config.TableInfo.TypeAttribute
+	if attribute == "" {
+		return nil, nil, newValidationErr("type attribute", "missing type attribute of table "+h.config.TableInfo.TableName)
+	}
+	if len(registry) == 0 {
+		return nil, nil, newValidationErr("model registry", "missing registered models")
+	}
+	if err := h.validateConsistentRead(DynamoTableOrIndexName(filters.dynamoDBIndex), filters); err != nil {
+		return nil, nil, err
+	}
+
+	// the filters are copied as they are reused for the next pages
+	typed := *filters
+	typed.AndCondition(string(attribute), registry.modelTypes(), IN)
+	typed.cursorSecret = h.config.CursorSecret
+	query, err := typed.BuildQueryInput()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := h.QueryWithContext(ctx, query)
+	if err != nil {
+		return nil, nil, translateErr(err)
+	}
+
+	records := make([]BaseModel, 0, len(res.Items))
+	for _, item := range res.Items {
+		var modelType DBModelName
+		if value, ok := item[string(attribute)]; ok {
+			modelType = DBModelName(aws.StringValue(value.S))
+		}
+		mdl, ok := registry[modelType]
+		if !ok {
+			return nil, nil, &ModelTypeError{Actual: modelType}
+		}
+		rec, err := mdl.Unmarshal(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, res.LastEvaluatedKey, nil
+}
@@ -0,0 +1,92 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+)
+
+// noteModel a second model type stored along with TestBaseModel
+type noteModel struct {
+	Text string
+}
+
+// GetModelType returns the model type
+func (noteModel) GetModelType() DBModelName {
+	return "note"
+}
+
+// Marshal translates the model to dynamodb object
+func (n noteModel) Marshal() (DBMap, error) {
+	return dynamodbattribute.MarshalMap(n)
+}
+
+// Unmarshal translates the dynamodb object to a note
+func (n noteModel) Unmarshal(data DBMap) (BaseModel, error) {
+	err := dynamodbattribute.UnmarshalMap(data, &n)
+	return n, err
+}
+
+// GetPartSortKey returns the note partition key
+func (n noteModel) GetPartSortKey(_ *DynamoTableOrIndexName) DBPSKeyValues {
+	return NewDbPSKeyValues(DBKeyValue(n.Text), nil)
+}
+
+func TestHandlerImp_QueryModels(t *testing.T) {
+	ctx := context.Background()
+	registry := NewModelRegistry(TestBaseModel{}, noteModel{})
+	filters := func() *AwsExpressionWrapper {
+		return NewExpressionWrapper("table").WithKeyCondition("partKey", "golang", EQUAL)
+	}
+	items := []map[string]*dynamodb.AttributeValue{
+		{"type": {S: aws.String("TestBaseModel")}, "Name": {S: aws.String("golang")}},
+		{"type": {S: aws.String("note")}, "Text": {S: aws.String("first")}},
+		{"type": {S: aws.String("note")}, "Text": {S: aws.String("second")}},
+	}
+
+	t.Run("successfully", func(t *testing.T) {
+		repo := handlerImp{config: typedCfg, DynamoDBAPI: MockQuery{Resp: dynamodb.QueryOutput{Items: items}}}
+
+		records, _, err := repo.QueryModels(ctx, registry, filters())
+		assert.NoError(t, err)
+		assert.Equal(t, []BaseModel{
+			TestBaseModel{Name: "golang"}, noteModel{Text: "first"}, noteModel{Text: "second"},
+		}, records)
+
+		grouped := GroupRecords(records)
+		assert.Len(t, grouped, 2)
+		assert.Equal(t, []noteModel{{Text: "first"}, {Text: "second"}}, RecordsOf[noteModel](grouped))
+		assert.Equal(t, []TestBaseModel{{Name: "golang"}}, RecordsOf[TestBaseModel](grouped))
+	})
+
+	t.Run("with unregistered model type", func(t *testing.T) {
+		repo := handlerImp{config: typedCfg, DynamoDBAPI: MockQuery{Resp: dynamodb.QueryOutput{Items: items}}}
+
+		_, _, err := repo.QueryModels(ctx, NewModelRegistry(TestBaseModel{}), filters())
+		assert.True(t, errors.Is(err, ErrModelTypeMismatch))
+	})
+
+	t.Run("with invalid input", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockQuery{}}
+		_, _, err := repo.QueryModels(ctx, registry, filters())
+		assert.True(t, errors.Is(err, ErrValidation))
+
+		repo = handlerImp{config: typedCfg, DynamoDBAPI: MockQuery{}}
+		_, _, err = repo.QueryModels(ctx, ModelRegistry{}, filters())
+		assert.True(t, errors.Is(err, ErrValidation))
+
+		_, _, err = repo.QueryModels(ctx, registry, nil)
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+
+	t.Run("with db error", func(t *testing.T) {
+		repo := handlerImp{config: typedCfg, DynamoDBAPI: MockQuery{Err: errors.New("db error")}}
+		_, _, err := repo.QueryModels(ctx, registry, filters())
+		assert.Error(t, err)
+	})
+}
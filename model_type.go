@@ -0,0 +1,70 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ErrModelTypeMismatch is matched by errors.Is when a record of another model type is unmarshalled
+var ErrModelTypeMismatch = errors.New("model type mismatch")
+
+// ModelTypeError is returned when the type attribute of a record does not match the model type of the input,
+// Expected is empty if the model type of the record is not registered eg. in the ModelRegistry of QueryModels
+type ModelTypeError struct {
+	Expected DBModelName
+	Actual   DBModelName
+}
+
+// Error returns the error message along with both model types
+func (e *ModelTypeError) Error() string {
+	if e.Expected == "" {
+		return fmt.Sprintf("model type mismatch: record of type %s is not registered", e.Actual)
+	}
+	return fmt.Sprintf("model type mismatch: record of type %s can not be unmarshalled into %s", e.Actual, e.Expected)
+}
+
+// Is matches ErrModelTypeMismatch
+func (e *ModelTypeError) Is(target error) bool {
+	return target == ErrModelTypeMismatch
+}
+
+// setModelType writes the model type into the type attribute of the item if the table has one
+func (h handlerImp) setModelType(item DBMap, in BaseModel) {
+	attribute := h.config.TableInfo.TypeAttribute
+	if attribute == "" {
+		return
+	}
+	if modelType := in.GetModelType(); modelType != "" {
+		item[string(attribute)] = &dynamodb.AttributeValue{S: aws.String(string(modelType))}
+	}
+}
+
+// withTypeFilter returns a copy of the filters matching only the records of the input model type,
+// the filters are returned as is if the table has no type attribute
+func (h handlerImp) withTypeFilter(input BaseModel, filters *AwsExpressionWrapper) *AwsExpressionWrapper {
+	attribute := h.config.TableInfo.TypeAttribute
+	if attribute == "" || filters == nil || input == nil || input.GetModelType() == "" {
+		return filters
+	}
+	// the filters are copied as they are reused for the next pages
+	typed := *filters
+	return typed.AndCondition(string(attribute), string(input.GetModelType()), EQUAL)
+}
+
+// unmarshal unmarshals the item using the input model, the items of another model type are rejected
+// with a ModelTypeError, the items without type attribute are unmarshalled as is
+func (h handlerImp) unmarshal(input BaseModel, item DBMap) (BaseModel, error) {
+	attribute := h.config.TableInfo.TypeAttribute
+	if attribute != "" {
+		if value, ok := item[string(attribute)]; ok && value.S != nil {
+			actual := DBModelName(aws.StringValue(value.S))
+			if expected := input.GetModelType(); expected != "" && actual != expected {
+				return nil, &ModelTypeError{Expected: expected, Actual: actual}
+			}
+		}
+	}
+	return input.Unmarshal(item)
+}
@@ -0,0 +1,108 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+// typedCfg the test table storing the model type in the "type" attribute
+var typedCfg = func() DBConfig {
+	config := bulkCfg
+	config.TableInfo.TypeAttribute = "type"
+	return config
+}()
+
+func TestHandlerImp_setModelType(t *testing.T) {
+	sKey := DBKeyValue("sKey")
+	mdl := TestBaseModel{Name: "golang", SKey: string(sKey)}
+
+	item, _, err := handlerImp{config: typedCfg}.createPutItem(mdl, false, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestBaseModel", aws.StringValue(item["type"].S))
+
+	// the type is not written without type attribute
+	item, _, err = handlerImp{config: cfg}.createPutItem(mdl, false, false, false)
+	assert.NoError(t, err)
+	assert.NotContains(t, item, "type")
+}
+
+func TestHandlerImp_withTypeFilter(t *testing.T) {
+	repo := handlerImp{config: typedCfg}
+	filters := NewExpressionWrapper("table").WithCondition("Age", 12, GT)
+
+	scan, err := repo.withTypeFilter(TestBaseModel{}, filters).BuildScanInput()
+	assert.NoError(t, err)
+	assert.Equal(t, "(#0 > :0) AND (#1 = :1)", aws.StringValue(scan.FilterExpression))
+	assert.Equal(t, "type", aws.StringValue(scan.ExpressionAttributeNames["#1"]))
+	assert.Equal(t, "TestBaseModel", aws.StringValue(scan.ExpressionAttributeValues[":1"].S))
+
+	// the filters of the caller are not changed
+	scan, err = filters.BuildScanInput()
+	assert.NoError(t, err)
+	assert.Equal(t, "#0 > :0", aws.StringValue(scan.FilterExpression))
+
+	assert.Same(t, filters, handlerImp{config: cfg}.withTypeFilter(TestBaseModel{}, filters))
+
+	t.Run("scan with key condition", func(t *testing.T) {
+		keyFilters := NewExpressionWrapper("table").
+			WithKeyCondition("partKey", "golang", EQUAL).
+			AndKeyCondition("sortKey", "ORDER#", BEGINSWITH)
+
+		scan, err := repo.withTypeFilter(TestBaseModel{}, keyFilters).BuildScanInput()
+		assert.NoError(t, err)
+		assert.Equal(t, "((#0 = :0) AND (begins_with (#1, :1))) AND (#2 = :2)", aws.StringValue(scan.FilterExpression))
+		assert.Len(t, scan.ExpressionAttributeNames, 3)
+		assert.Len(t, scan.ExpressionAttributeValues, 3)
+		assert.Equal(t, "type", aws.StringValue(scan.ExpressionAttributeNames["#2"]))
+		assert.Equal(t, "TestBaseModel", aws.StringValue(scan.ExpressionAttributeValues[":2"].S))
+	})
+}
+
+func TestHandlerImp_unmarshalModelType(t *testing.T) {
+	ctx := context.Background()
+	sKey := DBKeyValue("sKey")
+	keys := NewDbPSKeyValues("golang", &sKey)
+	item := func(modelType string) DBMap {
+		item := DBMap{"Name": {S: aws.String("golang")}}
+		if modelType != "" {
+			item["type"] = &dynamodb.AttributeValue{S: aws.String(modelType)}
+		}
+		return item
+	}
+
+	cases := []struct {
+		name     string
+		config   DBConfig
+		item     DBMap
+		mismatch bool
+	}{
+		{name: "same model type", config: typedCfg, item: item("TestBaseModel")},
+		{name: "other model type", config: typedCfg, item: item("order"), mismatch: true},
+		{name: "without type attribute", config: typedCfg, item: item("")},
+		{name: "table without type attribute", config: cfg, item: item("order")},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			repo := handlerImp{config: tc.config, DynamoDBAPI: MockedGetItem{
+				Resp: dynamodb.GetItemOutput{Item: tc.item},
+			}}
+			res, err := repo.GetByID(ctx, TestBaseModel{}, "", keys)
+			assert.Equal(t, tc.mismatch, errors.Is(err, ErrModelTypeMismatch), err)
+
+			var typeErr *ModelTypeError
+			if tc.mismatch {
+				assert.True(t, errors.As(err, &typeErr))
+				assert.Equal(t, DBModelName("order"), typeErr.Actual)
+				assert.Nil(t, res)
+				return
+			}
+			assert.Equal(t, TestBaseModel{Name: "golang"}, res)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package dynamodb
+
+import (
+	"context"
+	"sync"
+)
+
+// ScanSegment holds the progress of a single parallel scan segment
+// LastEvaluatedKey is the key to resume the segment from, Done is set once the segment is fully scanned
+type ScanSegment struct {
+	Segment          int64
+	LastEvaluatedKey DBAttributeValues
+	Done             bool
+}
+
+// ScanPageFunc is called for every page read by a parallel scan segment along with the segment progress,
+// it is called concurrently from all the segments, returning an error stops the whole scan
+type ScanPageFunc func(ctx context.Context, segment ScanSegment, records []BaseModel) error
+
+// ParallelScan scans the table using totalSegments concurrent workers, each worker reads a single segment
+// and passes the unmarshalled records page by page to fn.
+// resume holds the progress of the segments (as passed to fn) of an interrupted scan, finished segments are skipped.
+// the first error cancels the remaining workers and is returned
+func (h handlerImp) ParallelScan(
+	ctx context.Context, input BaseModel, filters *AwsExpressionWrapper, totalSegments int64, resume []ScanSegment, fn ScanPageFunc,
+) error {
+	h = h.route(input, filters)
+	if totalSegments < 1 {
+		return newValidationErr("segments", "total segments should be at least 1")
+	}
+	if fn == nil {
+		return newValidationErr("fn", "missing scan page function")
+	}
+	if filters == nil {
+		filters = NewExpressionWrapper(h.config.TableInfo.TableName)
+	}
+
+	progress := make(map[int64]ScanSegment, len(resume))
+	for _, segment := range resume {
+		if segment.Segment < 0 || segment.Segment >= totalSegments {
+			return newValidationErr("segments", "resume segment is out of range")
+		}
+		progress[segment.Segment] = segment
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var scanErr error
+
+	for segment := int64(0); segment < totalSegments; segment++ {
+		state, ok := progress[segment]
+		if !ok {
+			state = ScanSegment{Segment: segment}
+		}
+		if state.Done {
+			continue
+		}
+
+		wg.Add(1)
+		go func(state ScanSegment) {
+			defer wg.Done()
+			if err := h.scanSegment(ctx, input, filters, totalSegments, state, fn); err != nil {
+				once.Do(func() {
+					scanErr = err
+					cancel()
+				})
+			}
+		}(state)
+	}
+
+	wg.Wait()
+	return scanErr
+}
+
+// scanSegment reads all the pages of a single segment
+func (h handlerImp) scanSegment(
+	ctx context.Context, input BaseModel, filters *AwsExpressionWrapper, totalSegments int64, state ScanSegment, fn ScanPageFunc,
+) error {
+	// every segment works on its own copy of the filters
+	segmentFilters := *filters
+	segmentFilters.cursor = ""
+	segmentFilters.WithSegment(state.Segment, totalSegments)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		segmentFilters.WithExlusiveStartingKey(state.LastEvaluatedKey)
+		records, lastKey, err := h.GetRecordsWithScanFilter(ctx, input, &segmentFilters)
+		if err != nil {
+			return err
+		}
+
+		state.LastEvaluatedKey = lastKey
+		state.Done = len(lastKey) == 0
+		if err := fn(ctx, state, records); err != nil {
+			return err
+		}
+		if state.Done {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerImp_ParallelScan(t *testing.T) {
+	lastKey := map[string]*dynamodb.AttributeValue{
+		"partKey": {S: aws.String("key")},
+	}
+	createPages := func() map[int64][]dynamodb.ScanOutput {
+		return map[int64][]dynamodb.ScanOutput{
+			0: {
+				{Items: createIteratorPageItems("a"), LastEvaluatedKey: lastKey},
+				{Items: createIteratorPageItems("b")},
+			},
+			1: {
+				{Items: createIteratorPageItems("c")},
+			},
+			2: {
+				{Items: createIteratorPageItems("d", "e")},
+			},
+		}
+	}
+
+	t.Run("successfully", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: &MockSegmentedScan{Pages: createPages()}}
+
+		var mu sync.Mutex
+		names := make([]string, 0)
+		progress := make(map[int64]ScanSegment)
+		err := repo.ParallelScan(context.Background(), TestBaseModel{}, nil, 3, nil,
+			func(_ context.Context, segment ScanSegment, records []BaseModel) error {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, rec := range records {
+					names = append(names, rec.(TestBaseModel).Name)
+				}
+				progress[segment.Segment] = segment
+				return nil
+			},
+		)
+		assert.NoError(t, err)
+		sort.Strings(names)
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+		assert.Len(t, progress, 3)
+		for _, segment := range progress {
+			assert.True(t, segment.Done)
+		}
+	})
+
+	t.Run("resume skips finished segments", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: &MockSegmentedScan{Pages: createPages()}}
+		resume := []ScanSegment{
+			{Segment: 1, Done: true},
+			{Segment: 2, Done: true},
+		}
+
+		var mu sync.Mutex
+		segments := make(map[int64]bool)
+		err := repo.ParallelScan(context.Background(), TestBaseModel{}, nil, 3, resume,
+			func(_ context.Context, segment ScanSegment, _ []BaseModel) error {
+				mu.Lock()
+				defer mu.Unlock()
+				segments[segment.Segment] = true
+				return nil
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, map[int64]bool{0: true}, segments)
+	})
+
+	t.Run("first error stops the scan", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: &MockSegmentedScan{Pages: createPages()}}
+		expectedErr := errors.New("callback error")
+
+		err := repo.ParallelScan(context.Background(), TestBaseModel{}, nil, 3, nil,
+			func(context.Context, ScanSegment, []BaseModel) error {
+				return expectedErr
+			},
+		)
+		assert.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("with db error", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: &MockSegmentedScan{Err: errors.New("db error")}}
+		err := repo.ParallelScan(context.Background(), TestBaseModel{}, nil, 2, nil,
+			func(context.Context, ScanSegment, []BaseModel) error {
+				return nil
+			},
+		)
+		assert.Error(t, err)
+	})
+
+	t.Run("with canceled context", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: &MockSegmentedScan{Pages: createPages()}}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := repo.ParallelScan(ctx, TestBaseModel{}, nil, 2, nil,
+			func(context.Context, ScanSegment, []BaseModel) error {
+				return nil
+			},
+		)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("with invalid input", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: &MockSegmentedScan{}}
+		fn := func(context.Context, ScanSegment, []BaseModel) error { return nil }
+
+		assert.ErrorIs(t, repo.ParallelScan(context.Background(), TestBaseModel{}, nil, 0, nil, fn), ErrValidation)
+		assert.ErrorIs(t, repo.ParallelScan(context.Background(), TestBaseModel{}, nil, 2, nil, nil), ErrValidation)
+		assert.ErrorIs(t, repo.ParallelScan(context.Background(), TestBaseModel{}, nil, 2,
+			[]ScanSegment{{Segment: 2}}, fn,
+		), ErrValidation)
+	})
+}
+
+func TestAwsExpressionWrapper_WithSegment(t *testing.T) {
+	input, err := NewExpressionWrapper("table").
+		WithSegment(1, 4).
+		BuildScanInput()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), aws.Int64Value(input.Segment))
+	assert.Equal(t, int64(4), aws.Int64Value(input.TotalSegments))
+}
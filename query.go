@@ -2,71 +2,97 @@ package dynamodb
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
 func (h handlerImp) GetByID(ctx context.Context, input BaseModel, index DynamoTableOrIndexName, dbKeys DBPSKeyValues) (BaseModel, error) {
-	req, err := h.prepareGetReq(index, dbKeys)
+	h = h.route(input, nil)
+	req, err := h.prepareGetReq(index, dbKeys, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	res, getErr := h.GetItemWithContext(ctx, req)
 	if getErr != nil {
-		return nil, getErr
+		return nil, translateErr(getErr)
 	}
 
 	if len(res.Item) < 1 {
 		return nil, nil
 	}
 
-	mdl, mErr := input.Unmarshal(res.Item)
+	mdl, mErr := h.unmarshal(input, res.Item)
 	return mdl, mErr
 }
 
-func (h handlerImp) GetByIDs(ctx context.Context, input BaseModel, dbKeys []DBPSKeyValues) ([]BaseModel, error) {
-	pages := Partition(len(dbKeys), 25)
-	ch := make(chan baseModelsWithErr, len(pages))
+// FindByID gets the record by partition (& sort) key(s) using the read options eg. WithConsistentRead or WithProjection,
+// unlike GetByID it returns a NotFoundError, matching ErrNotFound, if the record does not exist
+func (h handlerImp) FindByID(
+	ctx context.Context, input BaseModel, index DynamoTableOrIndexName, dbKeys DBPSKeyValues, options *AwsExpressionWrapper,
+) (BaseModel, error) {
+	h = h.route(input, options)
+	req, err := h.prepareGetReq(index, dbKeys, options)
+	if err != nil {
+		return nil, err
+	}
 
-	for page := range pages {
-		go func(page IdxRange) {
-			req := h.buildGetRequests(dbKeys[page.Low:page.High])
-			h.loadPage(ctx, input, req, ch)
-		}(page)
+	res, err := h.GetItemWithContext(ctx, req)
+	if err != nil {
+		return nil, translateErr(err)
 	}
 
-	records := make([]BaseModel, 0, len(dbKeys))
-	for {
-		res, done := <-ch
-		if res.Err != nil {
-			return nil, res.Err
-		}
-		records = append(records, res.Records...)
+	if len(res.Item) < 1 {
+		return nil, &NotFoundError{Table: aws.StringValue(req.TableName), Keys: dbKeys}
+	}
+	return h.unmarshal(input, res.Item)
+}
 
-		if done {
-			break
-		}
+func (h handlerImp) GetByIDs(ctx context.Context, input BaseModel, dbKeys []DBPSKeyValues) ([]BaseModel, error) {
+	return h.GetByIDsWithOptions(ctx, input, dbKeys, nil)
+}
+
+// GetByIDsWithOptions get records by their partition (& sort) keys using the read options eg. WithConsistentRead,
+// the records are partially populated if a projection is set using WithProjection.
+// the invalid and missing keys are ignored, use BatchGetByIDs in order to get them
+func (h handlerImp) GetByIDsWithOptions(
+	ctx context.Context, input BaseModel, dbKeys []DBPSKeyValues, options *AwsExpressionWrapper,
+) ([]BaseModel, error) {
+	res, err := h.BatchGetByIDs(ctx, input, dbKeys, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Unprocessed) > 0 {
+		return nil, unprocessedKeysErr(res.Unprocessed)
 	}
-	return records, nil
+	return res.Records, nil
 }
 
 func (h handlerImp) GetRecordsWithScanFilter(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, DBAttributeValues, error) {
-	scanInput, err := filters.BuildScanInput()
+	h = h.route(input, filters)
+	filters = h.withTypeFilter(input, filters)
+	if err := h.validateConsistentRead(DynamoTableOrIndexName(filters.dynamoDBIndex), filters); err != nil {
+		return nil, nil, err
+	}
+	// the secret is set on a copy as the filters may be reused with other handlers
+	secured := *filters
+	secured.cursorSecret = h.config.CursorSecret
+	scanInput, err := secured.BuildScanInput()
 	if err != nil {
 		return nil, nil, err
 	}
 
 	res, getErr := h.ScanWithContext(ctx, scanInput)
 	if getErr != nil {
-		return nil, nil, getErr
+		return nil, nil, translateErr(getErr)
 	}
 
 	items := make([]BaseModel, 0, len(res.Items))
 
 	for _, item := range res.Items {
-		mdl, mErr := input.Unmarshal(item)
+		mdl, mErr := h.unmarshal(input, item)
 		if mErr != nil {
 			return nil, nil, mErr
 		}
@@ -77,20 +103,28 @@ func (h handlerImp) GetRecordsWithScanFilter(ctx context.Context, input BaseMode
 }
 
 func (h handlerImp) GetRecordsWithQueryFilter(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, DBAttributeValues, error) {
-	query, err := filters.BuildQueryInput()
+	h = h.route(input, filters)
+	filters = h.withTypeFilter(input, filters)
+	if err := h.validateConsistentRead(DynamoTableOrIndexName(filters.dynamoDBIndex), filters); err != nil {
+		return nil, nil, err
+	}
+	// the secret is set on a copy as the filters may be reused with other handlers
+	secured := *filters
+	secured.cursorSecret = h.config.CursorSecret
+	query, err := secured.BuildQueryInput()
 	if err != nil {
 		return nil, nil, err
 	}
 
 	res, getErr := h.QueryWithContext(ctx, query)
 	if getErr != nil {
-		return nil, nil, getErr
+		return nil, nil, translateErr(getErr)
 	}
 
 	items := make([]BaseModel, 0, len(res.Items))
 
 	for _, item := range res.Items {
-		mdl, mErr := input.Unmarshal(item)
+		mdl, mErr := h.unmarshal(input, item)
 		if mErr != nil {
 			return nil, nil, mErr
 		}
@@ -100,101 +134,58 @@ func (h handlerImp) GetRecordsWithQueryFilter(ctx context.Context, input BaseMod
 	return items, res.LastEvaluatedKey, nil
 }
 
-func (h handlerImp) prepareGetReq(name DynamoTableOrIndexName, keys DBPSKeyValues) (*dynamodb.GetItemInput, error) {
-	if len(keys.GetPartitionKey()) < 1 {
-		return nil, errors.New("invalid partition key")
+// GetRecordsWithScanCursor gets a page of records using scan req along with the cursor of the next page
+// the cursor is empty if there are no more pages
+func (h handlerImp) GetRecordsWithScanCursor(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, string, error) {
+	items, lastKey, err := h.GetRecordsWithScanFilter(ctx, input, filters)
+	if err != nil {
+		return nil, "", err
 	}
+	cursor, err := encodeCursor(lastKey, filters.dynamoDBTable, filters.dynamoDBIndex, h.config.CursorSecret)
+	return items, cursor, err
+}
 
-	dbKeys := h.config.TableInfo.DBPSKeyNames
-
-	if keys, ok := h.config.Indexes[name]; ok {
-		dbKeys = keys
+// GetRecordsWithQueryCursor gets a page of records using query req along with the cursor of the next page
+// the cursor is empty if there are no more pages
+func (h handlerImp) GetRecordsWithQueryCursor(ctx context.Context, input BaseModel, filters *AwsExpressionWrapper) ([]BaseModel, string, error) {
+	items, lastKey, err := h.GetRecordsWithQueryFilter(ctx, input, filters)
+	if err != nil {
+		return nil, "", err
 	}
+	cursor, err := encodeCursor(lastKey, filters.dynamoDBTable, filters.dynamoDBIndex, h.config.CursorSecret)
+	return items, cursor, err
+}
 
-	expr := NewExpressionWrapper(h.config.TableInfo.TableName).
-		WithPartitionKey(string(dbKeys.PartitionKey), string(keys.GetPartitionKey()))
-
-	if dbKeys.SortKey != nil && keys.GetSortKey() != nil {
-		expr.WithSortingKey(string(*dbKeys.SortKey), string(*keys.GetSortKey()))
+// prepareGetReq creates the get request of the keys, the read options are applied if provided
+func (h handlerImp) prepareGetReq(
+	name DynamoTableOrIndexName, keys DBPSKeyValues, options *AwsExpressionWrapper,
+) (*dynamodb.GetItemInput, error) {
+	if keys == nil || len(keys.GetPartitionKey()) < 1 {
+		return nil, newValidationErr(partitionKeyField, "invalid partition key")
 	}
 
-	return expr.BuildGetInput()
-}
-
-// buildGetRequests takes a list of ids and prepare list of BatchGetItemInput
-func (h handlerImp) buildGetRequests(ids []DBPSKeyValues) *dynamodb.BatchGetItemInput {
-	tabInfo := h.config.TableInfo
-	dbKeys := tabInfo.DBPSKeyNames
-	// create and accumulate the attributes for the input
-	attributes := make([]map[string]*dynamodb.AttributeValue, 0)
-	for _, id := range ids {
-		expr := NewExpressionWrapper(h.config.TableInfo.TableName).
-			WithPartitionKey(string(dbKeys.PartitionKey), string(id.GetPartitionKey()))
-
-		if dbKeys.SortKey != nil && id.GetSortKey() != nil {
-			expr.WithSortingKey(string(*dbKeys.SortKey), string(*id.GetSortKey()))
-		}
+	dbKeys := h.config.TableInfo.DBPSKeyNames
 
-		attribute, err := expr.CreateQueryKeys()
-		if err != nil {
-			// ignore wrong Ids
-			continue
-		}
-		attributes = append(attributes, attribute)
-	}
-	// create and return the Batch get request
-	return &dynamodb.BatchGetItemInput{
-		RequestItems: map[string]*dynamodb.KeysAndAttributes{
-			tabInfo.TableName: {
-				Keys: attributes,
-			},
-		},
+	if keys, ok := h.config.Indexes[name]; ok {
+		dbKeys = keys
 	}
-}
 
-func (h handlerImp) loadPage(ctx context.Context, model BaseModel, req *dynamodb.BatchGetItemInput, ch chan baseModelsWithErr) {
-	records := make([]BaseModel, 0)
-	// deserialize received output
-	acc := func(res *dynamodb.BatchGetItemOutput) error {
-		for _, item := range res.Responses[h.config.TableInfo.TableName] {
-			mdl, err := model.Unmarshal(item)
-			if err != nil {
-				return err
-			}
-			records = append(records, mdl)
-		}
-		return nil
+	if options == nil {
+		options = NewExpressionWrapper(h.config.TableInfo.TableName)
+	}
+	if err := h.validateConsistentRead(name, options); err != nil {
+		return nil, err
 	}
 
-	var load func(req *dynamodb.BatchGetItemInput) error
-
-	load = func(req *dynamodb.BatchGetItemInput) error {
-		var res *dynamodb.BatchGetItemOutput
-		var err error
-
-		if req != nil {
-			res, err = h.BatchGetItemWithContext(ctx, req)
-			if err != nil {
-				return err
-			}
-		}
+	return options.
+		WithKeys(dbKeys, keys).
+		BuildGetInput()
+}
 
-		if res != nil && len(res.Responses) > 0 {
-			if err := acc(res); err != nil {
-				return err
-			}
-		}
-		if len(res.UnprocessedKeys) > 0 {
-			return load(&dynamodb.BatchGetItemInput{
-				RequestItems: res.UnprocessedKeys,
-			})
-		}
+// validateConsistentRead rejects the consistent reads of global secondary indexes, which dynamodb does not support
+func (h handlerImp) validateConsistentRead(index DynamoTableOrIndexName, expr *AwsExpressionWrapper) error {
+	if !aws.BoolValue(expr.consistentRead) || !h.config.isGlobalIndex(index) {
 		return nil
 	}
-
-	err := load(req)
-	ch <- baseModelsWithErr{
-		Records: records,
-		Err:     err,
-	}
+	return newValidationErr("consistent read", fmt.Sprintf("consistent reads are not supported on global secondary index %s", index))
 }
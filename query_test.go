@@ -203,6 +203,60 @@ func TestHandler_GetByID(t *testing.T) {
 	}
 }
 
+func TestHandler_FindByID(t *testing.T) {
+	ctx := context.Background()
+	sKey := DBKeyValue("sKey")
+	dbKeys := dbPSKeyValues{partitionKey: "pKey", sortKey: &sKey}
+
+	t.Run("successfully", func(t *testing.T) {
+		repo := handlerImp{
+			config: cfg,
+			DynamoDBAPI: MockedGetItem{
+				Resp: dynamodb.GetItemOutput{
+					Item: DBMap{"name": {S: aws.String("golang")}},
+				},
+			},
+		}
+		options := NewExpressionWrapper(cfg.TableInfo.TableName).WithConsistentRead(true).WithProjection("name")
+		res, err := repo.FindByID(ctx, TestBaseModel{}, "", dbKeys, options)
+		assert.NoError(t, err)
+		assert.Equal(t, "golang", res.(TestBaseModel).Name)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockedGetItem{}}
+		res, err := repo.FindByID(ctx, TestBaseModel{}, "", dbKeys, nil)
+		assert.Nil(t, res)
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.EqualError(t, err, "record not found in table: pKey, sKey")
+
+		var notFoundErr *NotFoundError
+		assert.True(t, errors.As(err, &notFoundErr))
+		assert.Equal(t, dbKeys, notFoundErr.Keys)
+	})
+
+	t.Run("with invalid keys", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockedGetItem{}}
+		_, err := repo.FindByID(ctx, TestBaseModel{}, "", nil, nil)
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+
+	t.Run("with read options", func(t *testing.T) {
+		options := NewExpressionWrapper(cfg.TableInfo.TableName).WithConsistentRead(true).WithProjection("name", "Age")
+		req, err := handlerImp{config: cfg}.prepareGetReq("", dbKeys, options)
+		assert.NoError(t, err)
+		assert.True(t, aws.BoolValue(req.ConsistentRead))
+		assert.Equal(t, "#0, #1", aws.StringValue(req.ProjectionExpression))
+		assert.Equal(t, "Age", aws.StringValue(req.ExpressionAttributeNames["#1"]))
+		assert.Equal(t, "pKey", aws.StringValue(req.Key[string(pKey)].S))
+
+		req, err = handlerImp{config: cfg}.prepareGetReq("", dbKeys, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, req.ConsistentRead)
+		assert.Nil(t, req.ProjectionExpression)
+	})
+}
+
 func TestHandler_GetRecordsWithScanFilter(t *testing.T) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -642,3 +696,107 @@ func TestHandler_GetByIDs(t *testing.T) {
 		assert.NotEmpty(t, res)
 	})
 }
+
+func TestHandler_ConsistentRead(t *testing.T) {
+	ctx := context.Background()
+	config := cfg
+	config.Indexes = map[DynamoTableOrIndexName]DBPSKeyNames{
+		"by_email": {PartitionKey: "email"},
+		"by_date":  {PartitionKey: pKey, SortKey: &sKey, LocalIndex: true},
+	}
+	repo := handlerImp{
+		config: config,
+		DynamoDBAPI: MockQuery{
+			Resp: dynamodb.QueryOutput{Items: createIteratorPageItems("golang")},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		index    string
+		hasError bool
+	}{
+		{name: "table"},
+		{name: "local index", index: "by_date"},
+		{name: "global index", index: "by_email", hasError: true},
+		{name: "unknown index", index: "unknown", hasError: true},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			filters := NewExpressionWrapper(config.TableInfo.TableName).
+				WithIndexName(tc.index).
+				WithKeyCondition("name", "golang", EQUAL).
+				WithConsistentRead(true)
+			_, _, err := repo.GetRecordsWithQueryFilter(ctx, TestBaseModel{}, filters)
+			assert.Equal(t, tc.hasError, errors.Is(err, ErrValidation), err)
+		})
+	}
+
+	t.Run("eventually consistent global index", func(t *testing.T) {
+		filters := NewExpressionWrapper(config.TableInfo.TableName).
+			WithIndexName("by_email").
+			WithKeyCondition("email", "user@mail.com", EQUAL)
+		_, _, err := repo.GetRecordsWithQueryFilter(ctx, TestBaseModel{}, filters)
+		assert.NoError(t, err)
+	})
+
+	t.Run("get by id", func(t *testing.T) {
+		options := NewExpressionWrapper(config.TableInfo.TableName).WithConsistentRead(true)
+		_, err := repo.FindByID(ctx, TestBaseModel{}, "by_email", NewDbPSKeyValues("user@mail.com", nil), options)
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+
+	t.Run("get by ids", func(t *testing.T) {
+		options := NewExpressionWrapper(config.TableInfo.TableName).WithConsistentRead(true)
+		template, err := batchGetTemplate(config.TableInfo.DBPSKeyNames, options)
+		assert.NoError(t, err)
+		assert.True(t, aws.BoolValue(template.ConsistentRead))
+
+		options.WithIndexName("by_email")
+		_, err = repo.GetByIDsWithOptions(ctx, TestBaseModel{}, nil, options)
+		assert.True(t, errors.Is(err, ErrValidation))
+	})
+}
+
+func TestHandler_GetByIDsWithProjection(t *testing.T) {
+	ctx := context.Background()
+	options := NewExpressionWrapper(cfg.TableInfo.TableName).WithProjection("name", "Age")
+
+	keysAndAttributes, err := batchGetTemplate(cfg.TableInfo.DBPSKeyNames, options)
+	assert.NoError(t, err)
+	// the key attributes are added in order to match the items with the keys
+	assert.Equal(t, "#0, #1, #2, #3", aws.StringValue(keysAndAttributes.ProjectionExpression))
+	assert.Equal(t, "Age", aws.StringValue(keysAndAttributes.ExpressionAttributeNames["#1"]))
+	assert.Equal(t, "partKey", aws.StringValue(keysAndAttributes.ExpressionAttributeNames["#2"]))
+
+	// the projected key attributes are not added again
+	keysAndAttributes, err = batchGetTemplate(cfg.TableInfo.DBPSKeyNames,
+		NewExpressionWrapper(cfg.TableInfo.TableName).WithProjection("partKey", "Age", "meta.sortKey"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "#0, #1, #2.#3, #3", aws.StringValue(keysAndAttributes.ProjectionExpression))
+	assert.Equal(t, map[string]*string{
+		"#0": aws.String("partKey"), "#1": aws.String("Age"), "#2": aws.String("meta"), "#3": aws.String("sortKey"),
+	}, keysAndAttributes.ExpressionAttributeNames)
+
+	config, err := RegisterModel[taggedUser]("user", "users")
+	assert.NoError(t, err)
+	repo := handlerImp{
+		config: config,
+		DynamoDBAPI: MockedBatchGet{
+			TableName: config.TableInfo.TableName,
+			Resp: dynamodb.BatchGetItemOutput{
+				Responses: map[string][]map[string]*dynamodb.AttributeValue{
+					config.TableInfo.TableName: {{"name": {S: aws.String("golang")}}},
+				},
+			},
+		},
+	}
+	users, err := NewRepository[Model[taggedUser]](repo).GetByIDsWithOptions(ctx, []DBPSKeyValues{
+		NewModel(taggedUser{ID: "1", Email: "user@mail.com"}).GetPartSortKey(nil),
+	}, NewExpressionWrapper(config.TableInfo.TableName).WithProjection("name"))
+	assert.NoError(t, err)
+	// the records are partially populated
+	assert.Equal(t, []Model[taggedUser]{NewModel(taggedUser{Name: "golang"})}, users)
+}
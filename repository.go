@@ -0,0 +1,149 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+)
+
+// Repository provides typed access to the records of a single model on top of DBHandler
+// T should be the model value type (eg. User not *User) as its zero value is used to unmarshal the records
+//
+//	users := NewRepository[User](db)
+//	user, err := users.GetByID(ctx, "", NewDbPSKeyValues("123", nil))
+type Repository[T BaseModel] struct {
+	handler DBHandler
+	model   T
+}
+
+// NewRepository creates a typed repository using the provided db handler
+func NewRepository[T BaseModel](handler DBHandler) *Repository[T] {
+	var model T
+	return &Repository[T]{
+		handler: handler,
+		model:   model,
+	}
+}
+
+// Handler returns the underlying db handler
+func (r *Repository[T]) Handler() DBHandler {
+	return r.handler
+}
+
+// GetByID get by partition (& sort) key(s), returns the zero value of T if the record does not exist
+func (r *Repository[T]) GetByID(ctx context.Context, name DynamoTableOrIndexName, dbKeys DBPSKeyValues) (T, error) {
+	var empty T
+	res, err := r.handler.GetByID(ctx, r.model, name, dbKeys)
+	if err != nil || res == nil {
+		return empty, err
+	}
+	return castModel[T](res)
+}
+
+// Find get by partition (& sort) key(s) using the read options eg. WithConsistentRead or WithProjection,
+// returns ErrNotFound if the record does not exist, the projected records are partially populated
+func (r *Repository[T]) Find(
+	ctx context.Context, name DynamoTableOrIndexName, dbKeys DBPSKeyValues, options *AwsExpressionWrapper,
+) (T, error) {
+	var empty T
+	res, err := r.handler.FindByID(ctx, r.model, name, dbKeys, options)
+	if err != nil {
+		return empty, err
+	}
+	return castModel[T](res)
+}
+
+// GetByIDs get records by their partition (& sort) keys
+func (r *Repository[T]) GetByIDs(ctx context.Context, dbKeys []DBPSKeyValues) ([]T, error) {
+	res, err := r.handler.GetByIDs(ctx, r.model, dbKeys)
+	if err != nil {
+		return nil, err
+	}
+	return castModels[T](res)
+}
+
+// GetByIDsWithOptions get records by their partition (& sort) keys using the read options,
+// the records are partially populated if a projection is set using WithProjection
+func (r *Repository[T]) GetByIDsWithOptions(
+	ctx context.Context, dbKeys []DBPSKeyValues, options *AwsExpressionWrapper,
+) ([]T, error) {
+	res, err := r.handler.GetByIDsWithOptions(ctx, r.model, dbKeys, options)
+	if err != nil {
+		return nil, err
+	}
+	return castModels[T](res)
+}
+
+// Query gets a page of records that match the provided filter using query req
+func (r *Repository[T]) Query(ctx context.Context, filters *AwsExpressionWrapper) ([]T, DBAttributeValues, error) {
+	res, lastKey, err := r.handler.GetRecordsWithQueryFilter(ctx, r.model, filters)
+	if err != nil {
+		return nil, nil, err
+	}
+	records, err := castModels[T](res)
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, lastKey, nil
+}
+
+// Scan gets a page of records that match the provided filter using scan req
+func (r *Repository[T]) Scan(ctx context.Context, filters *AwsExpressionWrapper) ([]T, DBAttributeValues, error) {
+	res, lastKey, err := r.handler.GetRecordsWithScanFilter(ctx, r.model, filters)
+	if err != nil {
+		return nil, nil, err
+	}
+	records, err := castModels[T](res)
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, lastKey, nil
+}
+
+// Add inserts a new record
+func (r *Repository[T]) Add(ctx context.Context, record T, createSortKey bool) (DBPSKeyValues, error) {
+	return r.handler.AddRecord(ctx, record, createSortKey)
+}
+
+// Update replaces the record identified by dbKeys
+func (r *Repository[T]) Update(ctx context.Context, record T, dbKeys DBPSKeyValues) error {
+	return r.handler.UpdateRecordByID(ctx, record, dbKeys)
+}
+
+// Patch partially updates the record identified by dbKeys and returns it as T
+// returns the zero value of T if no attributes were returned eg. WithReturnValues(ReturnNone)
+func (r *Repository[T]) Patch(ctx context.Context, dbKeys DBPSKeyValues, update *AwsExpressionWrapper) (T, error) {
+	var empty T
+	res, err := r.handler.UpdateAndReturn(ctx, r.model, dbKeys, update)
+	if err != nil || res == nil {
+		return empty, err
+	}
+	return castModel[T](res)
+}
+
+// Delete deletes the record identified by dbKeys from the table of T if the passed filters were matched
+func (r *Repository[T]) Delete(ctx context.Context, dbKeys DBPSKeyValues, filters *AwsExpressionWrapper) error {
+	return r.handler.ForModel(r.model.GetModelType()).DeleteRecordByID(ctx, dbKeys, filters)
+}
+
+// castModel casts the unmarshalled base model to the repository type
+func castModel[T BaseModel](mdl BaseModel) (T, error) {
+	record, ok := mdl.(T)
+	if !ok {
+		var empty T
+		return empty, fmt.Errorf("unexpected model type %T, expected %T", mdl, empty)
+	}
+	return record, nil
+}
+
+// castModels casts the unmarshalled base models to the repository type
+func castModels[T BaseModel](models []BaseModel) ([]T, error) {
+	records := make([]T, 0, len(models))
+	for _, mdl := range models {
+		record, err := castModel[T](mdl)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
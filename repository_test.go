@@ -0,0 +1,133 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// otherTestModel a model used to check casting to a different type
+type otherTestModel struct {
+	TestBaseModel
+}
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	dbKeys := NewDbPSKeyValues("part", nil)
+	filters := NewExpressionWrapper(cfg.TableInfo.TableName)
+	expected := TestBaseModel{Name: "golang", Age: 12}
+
+	t.Run("get by id", func(t *testing.T) {
+		db := NewMockDBHandler(t)
+		db.On("GetByID", ctx, TestBaseModel{}, DynamoTableOrIndexName(""), dbKeys).Return(expected, nil)
+
+		res, err := NewRepository[TestBaseModel](db).GetByID(ctx, "", dbKeys)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("get by id not found", func(t *testing.T) {
+		db := NewMockDBHandler(t)
+		db.On("GetByID", ctx, TestBaseModel{}, DynamoTableOrIndexName(""), dbKeys).Return(nil, nil)
+
+		res, err := NewRepository[TestBaseModel](db).GetByID(ctx, "", dbKeys)
+		assert.NoError(t, err)
+		assert.Equal(t, TestBaseModel{}, res)
+	})
+
+	t.Run("find", func(t *testing.T) {
+		db := NewMockDBHandler(t)
+		options := NewExpressionWrapper(cfg.TableInfo.TableName).WithConsistentRead(true)
+		db.On("FindByID", ctx, TestBaseModel{}, DynamoTableOrIndexName(""), dbKeys, options).
+			Return(nil, &NotFoundError{Table: "table", Keys: dbKeys})
+
+		res, err := NewRepository[TestBaseModel](db).Find(ctx, "", dbKeys, options)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, TestBaseModel{}, res)
+	})
+
+	t.Run("get by id with unexpected type", func(t *testing.T) {
+		db := NewMockDBHandler(t)
+		db.On("GetByID", ctx, mock.Anything, DynamoTableOrIndexName(""), dbKeys).Return(expected, nil)
+
+		_, err := NewRepository[otherTestModel](db).GetByID(ctx, "", dbKeys)
+		assert.Error(t, err)
+	})
+
+	t.Run("get by ids", func(t *testing.T) {
+		db := NewMockDBHandler(t)
+		db.On("GetByIDs", ctx, TestBaseModel{}, []DBPSKeyValues{dbKeys}).Return([]BaseModel{expected}, nil)
+
+		res, err := NewRepository[TestBaseModel](db).GetByIDs(ctx, []DBPSKeyValues{dbKeys})
+		assert.NoError(t, err)
+		assert.Equal(t, []TestBaseModel{expected}, res)
+	})
+
+	t.Run("get by ids with db error", func(t *testing.T) {
+		db := NewMockDBHandler(t)
+		db.On("GetByIDs", ctx, TestBaseModel{}, []DBPSKeyValues{dbKeys}).Return(nil, errors.New("db error"))
+
+		res, err := NewRepository[TestBaseModel](db).GetByIDs(ctx, []DBPSKeyValues{dbKeys})
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		lastKey := DBAttributeValues{}
+		db := NewMockDBHandler(t)
+		db.On("GetRecordsWithQueryFilter", ctx, TestBaseModel{}, filters).Return([]BaseModel{expected}, lastKey, nil)
+
+		res, key, err := NewRepository[TestBaseModel](db).Query(ctx, filters)
+		assert.NoError(t, err)
+		assert.Equal(t, []TestBaseModel{expected}, res)
+		assert.Equal(t, lastKey, key)
+	})
+
+	t.Run("scan with unexpected type", func(t *testing.T) {
+		db := NewMockDBHandler(t)
+		db.On("GetRecordsWithScanFilter", ctx, mock.Anything, filters).Return([]BaseModel{expected}, nil, nil)
+
+		res, _, err := NewRepository[otherTestModel](db).Scan(ctx, filters)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("commands", func(t *testing.T) {
+		db := NewMockDBHandler(t)
+		db.On("AddRecord", ctx, expected, true).Return(dbKeys, nil)
+		db.On("UpdateRecordByID", ctx, expected, dbKeys).Return(nil)
+		db.On("ForModel", DBModelName("TestBaseModel")).Return(db)
+		db.On("DeleteRecordByID", ctx, dbKeys, filters).Return(nil)
+
+		repo := NewRepository[TestBaseModel](db)
+		keys, err := repo.Add(ctx, expected, true)
+		assert.NoError(t, err)
+		assert.Equal(t, dbKeys, keys)
+		assert.NoError(t, repo.Update(ctx, expected, dbKeys))
+		assert.NoError(t, repo.Delete(ctx, dbKeys, filters))
+
+		db.On("UpdateAndReturn", ctx, TestBaseModel{}, dbKeys, filters).Return(expected, nil)
+		patched, err := repo.Patch(ctx, dbKeys, filters)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, patched)
+		assert.Equal(t, db, repo.Handler())
+	})
+}
+
+func TestRepository_MultipleTables(t *testing.T) {
+	ctx := context.Background()
+	config := cfg
+	config.Tables = []DBTableConfig{ordersTable}
+	repo := handlerImp{config: config, DynamoDBAPI: MockDeleteItem{TableName: ordersTable.TableInfo.TableName}}
+
+	// the record is deleted from the table of the model without filters naming the table
+	err := NewRepository[orderModel](repo).Delete(ctx, NewDbPSKeyValues("1", nil), nil)
+	assert.NoError(t, err)
+
+	sortKey := DBKeyValue("sort")
+	err = NewRepository[TestBaseModel](repo).Delete(ctx, NewDbPSKeyValues("1", &sortKey), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,407 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+const (
+	// ModelTag the struct tag used to declare the table and index keys
+	ModelTag = "dyorm"
+	// partitionKeyTag marks the field as partition key
+	partitionKeyTag = "pk"
+	// sortKeyTag marks the field as sort key
+	sortKeyTag = "sk"
+	// indexTagPrefix marks the field as a key of an index eg. gsi:user_by_email,pk
+	indexTagPrefix = "gsi:"
+	// localIndexTagPrefix marks the field as a key of a local secondary index eg. lsi:user_by_date,sk
+	localIndexTagPrefix = "lsi:"
+	// versionTag marks the integer field as the optimistic locking version
+	versionTag = "version"
+)
+
+// modelKeyFields holds the field indexes of the partition and sort keys of a table or an index
+type modelKeyFields struct {
+	partitionKey int
+	sortKey      *int
+}
+
+// modelMeta holds the keys derived from the struct tags of a model
+type modelMeta struct {
+	modelType DBModelName
+	tableName string
+	table     modelKeyFields
+	indexes   map[DynamoTableOrIndexName]modelKeyFields
+	indexKeys map[DynamoTableOrIndexName]DBPSKeyNames
+	tableKeys DBPSKeyNames
+	// localIndexes share the partition key of the table
+	localIndexes map[DynamoTableOrIndexName]bool
+	// version the field index of the version attribute if any
+	version     *int
+	versionName FieldName
+	err         error
+}
+
+// modelRegistry caches the parsed model metadata keyed by the model reflect type
+var modelRegistry sync.Map
+
+// Model derives the BaseModel implementation of T out of its struct tags
+// the partition and sort keys are declared with `dyorm:"pk"` and `dyorm:"sk"`,
+// index keys with `dyorm:"gsi:index_name,pk"` or `dyorm:"lsi:index_name,sk"`, multiple tags are separated by `;`
+// an integer field tagged with `dyorm:"version"` enables the optimistic locking of VersionedModel
+// the attribute names are taken from the dynamodbav or json tags, as dynamodbattribute does
+//
+//	type User struct {
+//		ID    string `json:"user_id" dyorm:"pk"`
+//		Email string `json:"email_address" dyorm:"sk;gsi:user_by_email,pk"`
+//	}
+//	cfg, err := RegisterModel[User]("user", "users")
+//	_, err = db.AddRecord(ctx, NewModel(User{ID: "123"}), false)
+type Model[T any] struct {
+	Value T
+}
+
+// NewModel wraps the value into a BaseModel
+func NewModel[T any](value T) Model[T] {
+	return Model[T]{Value: value}
+}
+
+// RegisterModel parses the struct tags of T, registers T under modelType
+// and returns the DBConfig derived for the provided table
+func RegisterModel[T any](modelType DBModelName, tableName string) (DBConfig, error) {
+	var value T
+	meta := parseModelMeta(reflect.TypeOf(value), modelType, tableName)
+	if meta.err != nil {
+		return DBConfig{}, meta.err
+	}
+	modelRegistry.Store(reflect.TypeOf(value), meta)
+	return meta.config(), nil
+}
+
+// GetModelType returns the registered model type, or the struct name if T was not registered
+func (m Model[T]) GetModelType() DBModelName {
+	return m.meta().modelType
+}
+
+// Marshal marshals the wrapped value to dynamo map
+func (m Model[T]) Marshal() (DBMap, error) {
+	if err := m.meta().err; err != nil {
+		return nil, err
+	}
+	return dynamodbattribute.MarshalMap(m.Value)
+}
+
+// Unmarshal the received dynamo map to a new Model[T]
+func (m Model[T]) Unmarshal(dbMap DBMap) (BaseModel, error) {
+	mdl := Model[T]{}
+	err := dynamodbattribute.UnmarshalMap(dbMap, &mdl.Value)
+	return mdl, err
+}
+
+// GetPartSortKey returns the record's partition and sort key for the table or the provided index
+func (m Model[T]) GetPartSortKey(name *DynamoTableOrIndexName) DBPSKeyValues {
+	meta := m.meta()
+	if meta.err != nil {
+		return dbPSKeyValues{}
+	}
+
+	keyFields := meta.table
+	if name != nil {
+		fields, ok := meta.indexes[*name]
+		if !ok {
+			return dbPSKeyValues{}
+		}
+		keyFields = fields
+	}
+
+	value := reflect.ValueOf(m.Value)
+	keys := dbPSKeyValues{
+		partitionKey: fieldKeyValue(value.Field(keyFields.partitionKey)),
+	}
+	if keyFields.sortKey != nil {
+		sortKey := fieldKeyValue(value.Field(*keyFields.sortKey))
+		keys.sortKey = &sortKey
+	}
+	return keys
+}
+
+// GetVersion returns the version attribute name and value, the name is empty if T has no version field
+func (m Model[T]) GetVersion() (FieldName, int64) {
+	meta := m.meta()
+	if meta.err != nil || meta.version == nil {
+		return "", 0
+	}
+	value := reflect.ValueOf(m.Value).Field(*meta.version)
+	if value.CanUint() {
+		return meta.versionName, int64(value.Uint())
+	}
+	return meta.versionName, value.Int()
+}
+
+// meta returns the registered metadata of T or parses it on first use
+func (m Model[T]) meta() *modelMeta {
+	modelType := reflect.TypeOf(m.Value)
+	if meta, ok := modelRegistry.Load(modelType); ok {
+		return meta.(*modelMeta)
+	}
+	meta := parseModelMeta(modelType, "", "")
+	actual, _ := modelRegistry.LoadOrStore(modelType, meta)
+	return actual.(*modelMeta)
+}
+
+// config returns the DBConfig derived from the model keys
+func (meta *modelMeta) config() DBConfig {
+	indexes := make(map[DynamoTableOrIndexName]DBPSKeyNames, len(meta.indexKeys))
+	for name, keys := range meta.indexKeys {
+		indexes[name] = keys
+	}
+	return DBConfig{
+		TableInfo: DBTableInfo{
+			TableName:    meta.tableName,
+			DBPSKeyNames: meta.tableKeys,
+		},
+		Indexes: indexes,
+	}
+}
+
+// parseModelMeta parses the dyorm tags of the struct fields
+func parseModelMeta(modelType reflect.Type, name DBModelName, tableName string) *modelMeta {
+	meta := &modelMeta{
+		modelType:    name,
+		tableName:    tableName,
+		table:        modelKeyFields{partitionKey: -1},
+		indexes:      make(map[DynamoTableOrIndexName]modelKeyFields),
+		indexKeys:    make(map[DynamoTableOrIndexName]DBPSKeyNames),
+		localIndexes: make(map[DynamoTableOrIndexName]bool),
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		meta.err = fmt.Errorf("model %v should be a struct", modelType)
+		return meta
+	}
+	if meta.modelType == "" {
+		meta.modelType = DBModelName(modelType.Name())
+	}
+
+	for idx := 0; idx < modelType.NumField(); idx++ {
+		field := modelType.Field(idx)
+		tag, ok := field.Tag.Lookup(ModelTag)
+		if !ok || !field.IsExported() {
+			continue
+		}
+		keys, err := ParseModelTag(tag)
+		if err != nil {
+			meta.err = fmt.Errorf("field %s: %w", field.Name, err)
+			return meta
+		}
+		attrName := DBKeyName(AttributeName(field.Name, field.Tag))
+		keyType := fieldKeyType(field.Type)
+		for _, key := range keys {
+			if key.Version && !isIntegerKind(field.Type.Kind()) {
+				meta.err = fmt.Errorf("field %s: the version should be an integer", field.Name)
+				return meta
+			}
+			if !key.Version && !isKeyKind(field.Type) {
+				meta.err = fmt.Errorf("field %s: %v can not be used as a string, number or binary key", field.Name, field.Type)
+				return meta
+			}
+			if err := meta.addKey(key, idx, attrName, keyType); err != nil {
+				meta.err = fmt.Errorf("field %s: %w", field.Name, err)
+				return meta
+			}
+		}
+	}
+
+	if meta.table.partitionKey < 0 {
+		meta.err = fmt.Errorf("model %s is missing the partition key tag", modelType.Name())
+		return meta
+	}
+	for indexName, fields := range meta.indexes {
+		if fields.partitionKey < 0 && meta.localIndexes[indexName] {
+			fields.partitionKey = meta.table.partitionKey
+			keys := meta.indexKeys[indexName]
+			keys.PartitionKey = meta.tableKeys.PartitionKey
+			keys.PartitionKeyType = meta.tableKeys.PartitionKeyType
+			meta.indexes[indexName] = fields
+			meta.indexKeys[indexName] = keys
+		}
+		if fields.partitionKey < 0 {
+			meta.err = fmt.Errorf("index %s is missing the partition key tag", indexName)
+			return meta
+		}
+	}
+	return meta
+}
+
+// ModelKeyTag a single key declaration of the dyorm struct tag
+type ModelKeyTag struct {
+	// Index the index name, empty for the table keys
+	Index DynamoTableOrIndexName
+	// Local is set for the keys of a local secondary index
+	Local bool
+	// SortKey is set for sort keys, otherwise the field is a partition key
+	SortKey bool
+	// Version is set for the version attribute, which is not a key
+	Version bool
+}
+
+// ParseModelTag parses the value of a dyorm struct tag eg. "sk;gsi:user_by_email,pk"
+func ParseModelTag(tag string) ([]ModelKeyTag, error) {
+	keys := make([]ModelKeyTag, 0)
+	for _, key := range strings.Split(tag, ";") {
+		key = strings.TrimSpace(key)
+		switch {
+		case key == partitionKeyTag:
+			keys = append(keys, ModelKeyTag{})
+		case key == sortKeyTag:
+			keys = append(keys, ModelKeyTag{SortKey: true})
+		case key == versionTag:
+			keys = append(keys, ModelKeyTag{Version: true})
+		case strings.HasPrefix(key, indexTagPrefix), strings.HasPrefix(key, localIndexTagPrefix):
+			_, indexDef, _ := strings.Cut(key, ":")
+			indexName, keyType, found := strings.Cut(indexDef, ",")
+			if !found || indexName == "" {
+				return nil, fmt.Errorf("invalid index tag %q", key)
+			}
+			if keyType != partitionKeyTag && keyType != sortKeyTag {
+				return nil, fmt.Errorf("invalid index key type %q", keyType)
+			}
+			keys = append(keys, ModelKeyTag{
+				Index:   DynamoTableOrIndexName(indexName),
+				Local:   strings.HasPrefix(key, localIndexTagPrefix),
+				SortKey: keyType == sortKeyTag,
+			})
+		case key == "":
+		default:
+			return nil, fmt.Errorf("unknown key tag %q", key)
+		}
+	}
+	return keys, nil
+}
+
+// addKey adds a single key declaration eg. pk, sk or gsi:index_name,pk
+func (meta *modelMeta) addKey(key ModelKeyTag, fieldIdx int, attrName DBKeyName, keyType DBKeyType) error {
+	if key.Version {
+		if meta.version != nil {
+			return errors.New("duplicate version")
+		}
+		meta.version = &fieldIdx
+		meta.versionName = FieldName(attrName)
+		return nil
+	}
+	if key.Index == "" {
+		if !key.SortKey {
+			if meta.table.partitionKey >= 0 {
+				return errors.New("duplicate partition key")
+			}
+			meta.table.partitionKey = fieldIdx
+			meta.tableKeys.PartitionKey = attrName
+			meta.tableKeys.PartitionKeyType = keyType
+			return nil
+		}
+		if meta.table.sortKey != nil {
+			return errors.New("duplicate sort key")
+		}
+		meta.table.sortKey = &fieldIdx
+		meta.tableKeys.SortKey = &attrName
+		meta.tableKeys.SortKeyType = keyType
+		return nil
+	}
+
+	if key.Local {
+		meta.localIndexes[key.Index] = true
+	}
+	fields, ok := meta.indexes[key.Index]
+	if !ok {
+		fields = modelKeyFields{partitionKey: -1}
+	}
+	keys := meta.indexKeys[key.Index]
+	keys.LocalIndex = keys.LocalIndex || key.Local
+	if key.SortKey {
+		if fields.sortKey != nil {
+			return fmt.Errorf("duplicate sort key for index %s", key.Index)
+		}
+		fields.sortKey = &fieldIdx
+		keys.SortKey = &attrName
+		keys.SortKeyType = keyType
+	} else {
+		if fields.partitionKey >= 0 {
+			return fmt.Errorf("duplicate partition key for index %s", key.Index)
+		}
+		fields.partitionKey = fieldIdx
+		keys.PartitionKey = attrName
+		keys.PartitionKeyType = keyType
+	}
+	meta.indexes[key.Index] = fields
+	meta.indexKeys[key.Index] = keys
+	return nil
+}
+
+// AttributeName returns the dynamodb attribute name of a struct field following dynamodbattribute rules
+func AttributeName(fieldName string, tag reflect.StructTag) string {
+	for _, tagKey := range []string{"dynamodbav", "json"} {
+		if name, _, _ := strings.Cut(tag.Get(tagKey), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return fieldName
+}
+
+// fieldKeyType returns the key type of the struct field type, numbers are stored as N and byte slices as B
+func fieldKeyType(fieldType reflect.Type) DBKeyType {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return KeyTypeNumber
+	case reflect.Slice:
+		if fieldType.Elem().Kind() == reflect.Uint8 {
+			return KeyTypeBinary
+		}
+	}
+	return KeyTypeString
+}
+
+// isIntegerKind checks if the kind is a signed or unsigned integer
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// fieldKeyValue converts the field value to a key value, as it is marshalled eg. RFC3339 for time.Time
+func fieldKeyValue(value reflect.Value) DBKeyValue {
+	attr, err := dynamodbattribute.Marshal(value.Interface())
+	if err != nil {
+		return ""
+	}
+	return keyValueOf(attr)
+}
+
+// isKeyKind checks if the field type can be marshalled to a string, number or binary key
+// the struct types are expected to marshal to a string eg. time.Time
+func isKeyKind(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	switch fieldType.Kind() {
+	case reflect.String, reflect.Struct,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return fieldType.Elem().Kind() == reflect.Uint8
+	}
+	return false
+}
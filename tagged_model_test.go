@@ -0,0 +1,203 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+type taggedUser struct {
+	ID        string `json:"user_id" dyorm:"pk"`
+	Email     string `json:"email_address" dyorm:"sk;gsi:user_by_email,pk"`
+	Country   string `dynamodbav:"country" dyorm:"gsi:user_by_country,pk"`
+	CreatedAt int64  `json:"created_at" dyorm:"gsi:user_by_country,sk;lsi:user_by_date,sk"`
+	Name      string `json:"name"`
+}
+
+type taggedEvent struct {
+	Hash []byte `json:"hash" dyorm:"pk"`
+	Seq  int64  `json:"seq" dyorm:"sk;lsi:event_by_seq,sk"`
+}
+
+type taggedOrder struct {
+	UserID    string    `json:"user_id" dyorm:"pk"`
+	CreatedAt time.Time `json:"created_at" dyorm:"sk"`
+}
+
+type taggedWithBoolKey struct {
+	ID     string `dyorm:"pk"`
+	Active bool   `dyorm:"sk"`
+}
+
+type taggedWithoutPartitionKey struct {
+	ID string `dyorm:"sk"`
+}
+
+type taggedWithDuplicateKey struct {
+	ID    string `dyorm:"pk"`
+	Other string `dyorm:"pk"`
+}
+
+type taggedWithInvalidTag struct {
+	ID string `dyorm:"pk;gsi:index"`
+}
+
+type taggedUnregistered struct {
+	ID string `json:"id" dyorm:"pk"`
+}
+
+func TestRegisterModel(t *testing.T) {
+	t.Run("successfully", func(t *testing.T) {
+		config, err := RegisterModel[taggedUser]("user", "users")
+		assert.NoError(t, err)
+		assert.True(t, config.IsValid())
+		assert.Equal(t, "users", config.TableInfo.TableName)
+		assert.Equal(t, DBKeyName("user_id"), config.TableInfo.PartitionKey)
+		assert.Equal(t, DBKeyName("email_address"), *config.TableInfo.SortKey)
+
+		assert.Len(t, config.Indexes, 3)
+		assert.Equal(t, DBKeyName("email_address"), config.Indexes["user_by_email"].PartitionKey)
+		assert.Nil(t, config.Indexes["user_by_email"].SortKey)
+		assert.Equal(t, DBKeyName("country"), config.Indexes["user_by_country"].PartitionKey)
+		assert.Equal(t, DBKeyName("created_at"), *config.Indexes["user_by_country"].SortKey)
+		// local indexes share the table partition key
+		assert.Equal(t, DBKeyName("user_id"), config.Indexes["user_by_date"].PartitionKey)
+		assert.Equal(t, KeyTypeNumber, config.Indexes["user_by_date"].SortKeyType)
+		assert.True(t, config.Indexes["user_by_date"].LocalIndex)
+		assert.False(t, config.Indexes["user_by_email"].LocalIndex)
+	})
+
+	t.Run("with typed keys", func(t *testing.T) {
+		config, err := RegisterModel[taggedEvent]("event", "events")
+		assert.NoError(t, err)
+		assert.Equal(t, KeyTypeBinary, config.TableInfo.PartitionKeyType)
+		assert.Equal(t, KeyTypeNumber, config.TableInfo.SortKeyType)
+		assert.Equal(t, KeyTypeBinary, config.Indexes["event_by_seq"].PartitionKeyType)
+
+		keys := NewModel(taggedEvent{Hash: []byte{1, 2}, Seq: 7}).GetPartSortKey(nil)
+		assert.Equal(t, DBKeyValue([]byte{1, 2}), keys.GetPartitionKey())
+		assert.Equal(t, DBKeyValue("7"), *keys.GetSortKey())
+	})
+
+	t.Run("with time key", func(t *testing.T) {
+		config, err := RegisterModel[taggedOrder]("order", "orders")
+		assert.NoError(t, err)
+		assert.Equal(t, KeyTypeString, config.TableInfo.SortKeyType)
+
+		order := NewModel(taggedOrder{UserID: "1", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+		// the key holds the marshalled value, which is written over the attribute
+		assert.Equal(t, DBKeyValue("2024-01-01T00:00:00Z"), *order.GetPartSortKey(nil).GetSortKey())
+
+		item, _, err := handlerImp{config: config}.createPutItem(order, false, false, false)
+		assert.NoError(t, err)
+		res, err := Model[taggedOrder]{}.Unmarshal(item)
+		assert.NoError(t, err)
+		assert.Equal(t, order, res)
+	})
+
+	cases := []struct {
+		name     string
+		register func(modelType DBModelName, tableName string) (DBConfig, error)
+	}{
+		{
+			name:     "unsupported key type",
+			register: RegisterModel[taggedWithBoolKey],
+		},
+		{
+			name:     "missing partition key",
+			register: RegisterModel[taggedWithoutPartitionKey],
+		},
+		{
+			name:     "duplicate partition key",
+			register: RegisterModel[taggedWithDuplicateKey],
+		},
+		{
+			name:     "invalid index tag",
+			register: RegisterModel[taggedWithInvalidTag],
+		},
+		{
+			name:     "not a struct",
+			register: RegisterModel[*taggedUser],
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.register("model", "table")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestModel(t *testing.T) {
+	_, err := RegisterModel[taggedUser]("user", "users")
+	assert.NoError(t, err)
+	user := NewModel(taggedUser{
+		ID:        "123",
+		Email:     "user@mail.com",
+		Country:   "de",
+		CreatedAt: 1586190435,
+		Name:      "golang",
+	})
+
+	t.Run("model type", func(t *testing.T) {
+		assert.Equal(t, DBModelName("user"), user.GetModelType())
+		assert.Equal(t, DBModelName("taggedUnregistered"), NewModel(taggedUnregistered{}).GetModelType())
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		keys := user.GetPartSortKey(nil)
+		assert.Equal(t, DBKeyValue("123"), keys.GetPartitionKey())
+		assert.Equal(t, DBKeyValue("user@mail.com"), *keys.GetSortKey())
+
+		index := DynamoTableOrIndexName("user_by_country")
+		keys = user.GetPartSortKey(&index)
+		assert.Equal(t, DBKeyValue("de"), keys.GetPartitionKey())
+		assert.Equal(t, DBKeyValue("1586190435"), *keys.GetSortKey())
+
+		index = DynamoTableOrIndexName("unknown")
+		assert.Empty(t, user.GetPartSortKey(&index).GetPartitionKey())
+	})
+
+	t.Run("marshal and unmarshal", func(t *testing.T) {
+		item, err := user.Marshal()
+		assert.NoError(t, err)
+		assert.Equal(t, "123", aws.StringValue(item["user_id"].S))
+		assert.Equal(t, "de", aws.StringValue(item["country"].S))
+
+		res, err := Model[taggedUser]{}.Unmarshal(item)
+		assert.NoError(t, err)
+		assert.Equal(t, user, res)
+	})
+
+	t.Run("invalid model", func(t *testing.T) {
+		mdl := NewModel(taggedWithDuplicateKey{ID: "1"})
+		_, err := mdl.Marshal()
+		assert.Error(t, err)
+		assert.Empty(t, mdl.GetPartSortKey(nil).GetPartitionKey())
+	})
+
+	t.Run("with handler and repository", func(t *testing.T) {
+		config, _ := RegisterModel[taggedUser]("user", "users")
+		repo := handlerImp{
+			config: config,
+			DynamoDBAPI: MockedGetItem{
+				Resp: dynamodb.GetItemOutput{
+					Item: DBMap{
+						"user_id": {S: aws.String("123")},
+						"name":    {S: aws.String("golang")},
+					},
+				},
+			},
+		}
+
+		users := NewRepository[Model[taggedUser]](repo)
+		res, err := users.GetByID(context.Background(), "", user.GetPartSortKey(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "golang", res.Value.Name)
+	})
+}
@@ -0,0 +1,363 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/google/uuid"
+)
+
+// maxTransactItems the maximum number of items dynamodb accepts in a single transaction
+const maxTransactItems = 100
+
+// transactOperation the type of write operation within a transaction
+type transactOperation int
+
+const (
+	transactPut transactOperation = iota + 1
+	transactUpdate
+	transactDelete
+	transactConditionCheck
+)
+
+// TransactWriteItem defines a single write operation to be executed as part of a transaction
+type TransactWriteItem struct {
+	operation     transactOperation
+	model         BaseModel
+	createSortKey bool
+	dbKeys        DBPSKeyValues
+	expr          *AwsExpressionWrapper
+}
+
+// NewTransactPut creates a put operation for the provided model,
+// the put is applied only if the (optional) condition is matched, and for versioned models if the stored version matches
+func NewTransactPut(in BaseModel, createSortKey bool, condition *AwsExpressionWrapper) TransactWriteItem {
+	return TransactWriteItem{
+		operation:     transactPut,
+		model:         in,
+		createSortKey: createSortKey,
+		expr:          condition,
+	}
+}
+
+// NewTransactUpdate creates an update operation for the record identified by dbKeys,
+// the update fields and the (optional) condition are taken from the expression
+func NewTransactUpdate(dbKeys DBPSKeyValues, expr *AwsExpressionWrapper) TransactWriteItem {
+	return TransactWriteItem{
+		operation: transactUpdate,
+		dbKeys:    dbKeys,
+		expr:      expr,
+	}
+}
+
+// NewTransactDelete creates a delete operation for the record identified by dbKeys,
+// the delete is applied only if the (optional) condition is matched
+func NewTransactDelete(dbKeys DBPSKeyValues, condition *AwsExpressionWrapper) TransactWriteItem {
+	return TransactWriteItem{
+		operation: transactDelete,
+		dbKeys:    dbKeys,
+		expr:      condition,
+	}
+}
+
+// NewTransactConditionCheck creates a condition check on the record identified by dbKeys,
+// the whole transaction is canceled if the condition is not matched
+func NewTransactConditionCheck(dbKeys DBPSKeyValues, condition *AwsExpressionWrapper) TransactWriteItem {
+	return TransactWriteItem{
+		operation: transactConditionCheck,
+		dbKeys:    dbKeys,
+		expr:      condition,
+	}
+}
+
+// TransactGetItem defines a single record to be read as part of a read transaction
+type TransactGetItem struct {
+	model  BaseModel
+	dbKeys DBPSKeyValues
+}
+
+// NewTransactGetItem creates a read operation for the record identified by dbKeys,
+// the record is unmarshalled using the provided model
+func NewTransactGetItem(model BaseModel, dbKeys DBPSKeyValues) TransactGetItem {
+	return TransactGetItem{
+		model:  model,
+		dbKeys: dbKeys,
+	}
+}
+
+// TransactionCancelReason holds the reason for canceling a transaction
+// Index is the position of the failing item in the transaction request
+type TransactionCancelReason struct {
+	Index   int
+	Code    string
+	Message string
+	Item    DBMap
+}
+
+// TransactionCanceledError is returned when dynamodb cancels a transaction, it matches ErrTransactionCanceled
+// along with the kind of its reasons eg. ErrConditionFailed if a condition was not matched
+// Reasons holds only the items that caused the cancellation
+type TransactionCanceledError struct {
+	Reasons []TransactionCancelReason
+	err     error
+}
+
+// Error returns the error message along with the cancellation reasons
+func (e *TransactionCanceledError) Error() string {
+	reasons := make([]string, 0, len(e.Reasons))
+	for _, reason := range e.Reasons {
+		reasons = append(reasons, fmt.Sprintf("item %d: %s", reason.Index, reason.Code))
+	}
+	return fmt.Sprintf("transaction canceled [%s]", strings.Join(reasons, ", "))
+}
+
+// Is matches ErrTransactionCanceled and the error kinds of the cancellation reasons
+func (e *TransactionCanceledError) Is(target error) bool {
+	if target == ErrTransactionCanceled {
+		return true
+	}
+	for _, reason := range e.Reasons {
+		if cancelReasonKinds[reason.Code] == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the underlying dynamodb error
+func (e *TransactionCanceledError) Unwrap() error {
+	return e.err
+}
+
+// cancelReasonKinds maps the cancellation reason codes to the error kinds
+var cancelReasonKinds = map[string]error{
+	"ConditionalCheckFailed":          ErrConditionFailed,
+	"ProvisionedThroughputExceeded":   ErrThrottled,
+	"ThrottlingError":                 ErrThrottled,
+	"ItemCollectionSizeLimitExceeded": ErrItemTooLarge,
+	"ValidationError":                 ErrValidation,
+}
+
+// TransactWrite executes all the provided operations atomically using a single TransactWriteItems call
+func (h handlerImp) TransactWrite(ctx context.Context, requestToken string, items ...TransactWriteItem) error {
+	if len(items) < 1 {
+		return newValidationErr("transaction items", "missing transaction items")
+	}
+	if len(items) > maxTransactItems {
+		return newValidationErr("transaction items", fmt.Sprintf("transaction exceeds the maximum of %d items", maxTransactItems))
+	}
+
+	transactItems := make([]*dynamodb.TransactWriteItem, 0, len(items))
+	for _, item := range items {
+		// the items are routed to the table of their model or expression
+		transactItem, err := h.route(item.model, item.expr).buildTransactWriteItem(item)
+		if err != nil {
+			return err
+		}
+		transactItems = append(transactItems, transactItem)
+	}
+
+	if requestToken == "" {
+		requestToken = uuid.New().String()
+	}
+
+	input := dynamodb.TransactWriteItemsInput{
+		TransactItems:      transactItems,
+		ClientRequestToken: aws.String(requestToken),
+	}
+	_, err := h.TransactWriteItemsWithContext(ctx, &input)
+	return decodeTransactionErr(err)
+}
+
+// TransactGetByIDs reads all the provided records with a single TransactGetItems call,
+// the result has the same order as the provided items and holds nil for the records that do not exist
+func (h handlerImp) TransactGetByIDs(ctx context.Context, items ...TransactGetItem) ([]BaseModel, error) {
+	if len(items) < 1 {
+		return nil, newValidationErr("transaction items", "missing transaction items")
+	}
+	if len(items) > maxTransactItems {
+		return nil, newValidationErr("transaction items", fmt.Sprintf("transaction exceeds the maximum of %d items", maxTransactItems))
+	}
+
+	transactItems := make([]*dynamodb.TransactGetItem, 0, len(items))
+	for _, item := range items {
+		if item.model == nil {
+			return nil, newValidationErr("model", "missing transaction get model")
+		}
+		routed := h.route(item.model, nil)
+		keys, err := routed.createTransactKeys(item.dbKeys)
+		if err != nil {
+			return nil, err
+		}
+		transactItems = append(transactItems, &dynamodb.TransactGetItem{
+			Get: &dynamodb.Get{
+				Key:       keys,
+				TableName: aws.String(routed.config.TableInfo.TableName),
+			},
+		})
+	}
+
+	res, err := h.TransactGetItemsWithContext(ctx, &dynamodb.TransactGetItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		return nil, decodeTransactionErr(err)
+	}
+
+	records := make([]BaseModel, len(items))
+	for idx, response := range res.Responses {
+		if idx >= len(items) || response == nil || len(response.Item) < 1 {
+			continue
+		}
+		mdl, err := h.route(items[idx].model, nil).unmarshal(items[idx].model, response.Item)
+		if err != nil {
+			return nil, err
+		}
+		records[idx] = mdl
+	}
+	return records, nil
+}
+
+func (h handlerImp) buildTransactWriteItem(item TransactWriteItem) (*dynamodb.TransactWriteItem, error) {
+	tabInfo := h.config.TableInfo
+
+	if item.operation == transactPut {
+		if item.model == nil {
+			return nil, newValidationErr("model", "missing transaction put model")
+		}
+		dbItem, _, err := h.createPutItem(item.model, true, item.createSortKey, false)
+		if err != nil {
+			return nil, err
+		}
+		put := &dynamodb.Put{
+			Item:      dbItem,
+			TableName: aws.String(tabInfo.TableName),
+		}
+		var condition expression.ConditionBuilder
+		if item.expr != nil {
+			if item.expr.err != nil {
+				return nil, item.expr.err
+			}
+			condition = item.expr.conditionExpression
+		}
+		// versioned models are put only if the stored version matches the model version, which is incremented
+		version := modelVersion(item.model)
+		if version != nil {
+			dbItem[string(version.name)] = versionValue(version.expected + 1)
+		}
+		condition = withVersionCondition(condition, version)
+		if !reflect.DeepEqual(condition, expression.ConditionBuilder{}) {
+			awsExpression, err := expression.NewBuilder().WithCondition(condition).Build()
+			if err != nil {
+				return nil, err
+			}
+			put.ConditionExpression = awsExpression.Condition()
+			put.ExpressionAttributeNames = awsExpression.Names()
+			put.ExpressionAttributeValues = awsExpression.Values()
+		}
+		return &dynamodb.TransactWriteItem{Put: put}, nil
+	}
+
+	keys, err := h.createTransactKeys(item.dbKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	switch item.operation {
+	case transactUpdate:
+		if item.expr == nil {
+			return nil, newValidationErr("update", "their is nothing set to be updated, please use WithUpdateField")
+		}
+		awsExpression, err := item.expr.buildUpdate()
+		if err != nil {
+			return nil, err
+		}
+		return &dynamodb.TransactWriteItem{
+			Update: &dynamodb.Update{
+				Key:                       keys,
+				TableName:                 aws.String(tabInfo.TableName),
+				UpdateExpression:          awsExpression.Update(),
+				ConditionExpression:       awsExpression.Condition(),
+				ExpressionAttributeNames:  awsExpression.Names(),
+				ExpressionAttributeValues: awsExpression.Values(),
+			},
+		}, nil
+	case transactDelete:
+		del := &dynamodb.Delete{
+			Key:       keys,
+			TableName: aws.String(tabInfo.TableName),
+		}
+		condition, err := item.expr.buildCondition()
+		if err != nil {
+			return nil, err
+		}
+		if condition != nil {
+			del.ConditionExpression = condition.Condition()
+			del.ExpressionAttributeNames = condition.Names()
+			del.ExpressionAttributeValues = condition.Values()
+		}
+		return &dynamodb.TransactWriteItem{Delete: del}, nil
+	case transactConditionCheck:
+		condition, err := item.expr.buildCondition()
+		if err != nil {
+			return nil, err
+		}
+		if condition == nil {
+			return nil, newValidationErr("condition", "missing condition for transaction condition check")
+		}
+		return &dynamodb.TransactWriteItem{
+			ConditionCheck: &dynamodb.ConditionCheck{
+				Key:                       keys,
+				TableName:                 aws.String(tabInfo.TableName),
+				ConditionExpression:       condition.Condition(),
+				ExpressionAttributeNames:  condition.Names(),
+				ExpressionAttributeValues: condition.Values(),
+			},
+		}, nil
+	default:
+		return nil, newValidationErr("operation", "unknown transaction operation")
+	}
+}
+
+// createTransactKeys creates the table primary key out of the provided keys
+func (h handlerImp) createTransactKeys(dbKeys DBPSKeyValues) (map[string]*dynamodb.AttributeValue, error) {
+	tabInfo := h.config.TableInfo
+	if dbKeys == nil || len(dbKeys.GetPartitionKey()) < 1 {
+		return nil, newValidationErr(partitionKeyField, "missing required partition key")
+	}
+	if tabInfo.SortKey != nil && dbKeys.GetSortKey() == nil {
+		return nil, newValidationErr(sortKeyField, "missing required sort key")
+	}
+
+	return NewExpressionWrapper(tabInfo.TableName).
+		WithKeys(tabInfo.DBPSKeyNames, dbKeys).
+		CreateQueryKeys()
+}
+
+// decodeTransactionErr translates dynamodb's transaction cancellation into TransactionCanceledError
+func decodeTransactionErr(err error) error {
+	var canceledErr *dynamodb.TransactionCanceledException
+	if err == nil || !errors.As(err, &canceledErr) {
+		return translateErr(err)
+	}
+
+	reasons := make([]TransactionCancelReason, 0, len(canceledErr.CancellationReasons))
+	for idx, reason := range canceledErr.CancellationReasons {
+		if reason == nil || aws.StringValue(reason.Code) == "None" {
+			continue
+		}
+		reasons = append(reasons, TransactionCancelReason{
+			Index:   idx,
+			Code:    aws.StringValue(reason.Code),
+			Message: aws.StringValue(reason.Message),
+			Item:    reason.Item,
+		})
+	}
+	return &TransactionCanceledError{Reasons: reasons, err: err}
+}
@@ -0,0 +1,289 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerImp_TransactWrite(t *testing.T) {
+	validDBKeys := dbPSKeyValues{
+		partitionKey: "part",
+		sortKey: func() *DBKeyValue {
+			sKey := DBKeyValue("sKey")
+			return &sKey
+		}(),
+	}
+	cases := []struct {
+		name     string
+		items    []TransactWriteItem
+		dbError  error
+		hasError bool
+	}{
+		{
+			name: "successfully",
+			items: []TransactWriteItem{
+				NewTransactPut(TestBaseModel{Name: "golang", Age: 12}, true,
+					NewExpressionWrapper(cfg.TableInfo.TableName).WithCondition("Age", 10, GT),
+				),
+				NewTransactUpdate(validDBKeys, NewExpressionWrapper(cfg.TableInfo.TableName).
+					WithUpdateField("Age", 11).
+					WithCondition("Age", 10, GT),
+				),
+				NewTransactDelete(validDBKeys, nil),
+				NewTransactConditionCheck(validDBKeys, NewExpressionWrapper(cfg.TableInfo.TableName).
+					WithCondition("Age", 10, LE),
+				),
+			},
+		},
+		{
+			name:     "without items",
+			hasError: true,
+		},
+		{
+			name: "with too many items",
+			items: func() []TransactWriteItem {
+				items := make([]TransactWriteItem, 0, maxTransactItems+1)
+				for i := 0; i <= maxTransactItems; i++ {
+					items = append(items, NewTransactDelete(validDBKeys, nil))
+				}
+				return items
+			}(),
+			hasError: true,
+		},
+		{
+			name: "with marshalling error",
+			items: []TransactWriteItem{
+				NewTransactPut(TestBaseModel{withMarshallingErr: true}, true, nil),
+			},
+			hasError: true,
+		},
+		{
+			name: "update without update fields",
+			items: []TransactWriteItem{
+				NewTransactUpdate(validDBKeys, NewExpressionWrapper(cfg.TableInfo.TableName)),
+			},
+			hasError: true,
+		},
+		{
+			name: "delete with missing sort key",
+			items: []TransactWriteItem{
+				NewTransactDelete(dbPSKeyValues{partitionKey: "part"}, nil),
+			},
+			hasError: true,
+		},
+		{
+			name: "condition check without condition",
+			items: []TransactWriteItem{
+				NewTransactConditionCheck(validDBKeys, nil),
+			},
+			hasError: true,
+		},
+		{
+			name: "with db error",
+			items: []TransactWriteItem{
+				NewTransactDelete(validDBKeys, nil),
+			},
+			dbError:  errors.New("fake error"),
+			hasError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo := handlerImp{
+				config: cfg,
+				DynamoDBAPI: MockedTransactWrite{
+					Err: tc.dbError,
+				},
+			}
+			ctx := context.Background()
+			err := repo.TransactWrite(ctx, "", tc.items...)
+			assert.True(t, tc.hasError == (err != nil), fmt.Sprintf("%v", err))
+		})
+	}
+
+	t.Run("with canceled transaction", func(t *testing.T) {
+		repo := handlerImp{
+			config: cfg,
+			DynamoDBAPI: MockedTransactWrite{
+				Err: &dynamodb.TransactionCanceledException{
+					CancellationReasons: []*dynamodb.CancellationReason{
+						{Code: aws.String("None")},
+						{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("condition failed")},
+					},
+				},
+			},
+		}
+		ctx := context.Background()
+		err := repo.TransactWrite(ctx, "token",
+			NewTransactDelete(validDBKeys, nil),
+			NewTransactDelete(validDBKeys, nil),
+		)
+
+		var canceledErr *TransactionCanceledError
+		assert.True(t, errors.As(err, &canceledErr))
+		if canceledErr != nil {
+			assert.Len(t, canceledErr.Reasons, 1)
+			assert.Equal(t, 1, canceledErr.Reasons[0].Index)
+			assert.Equal(t, "ConditionalCheckFailed", canceledErr.Reasons[0].Code)
+		}
+		assert.True(t, errors.Is(err, ErrTransactionCanceled))
+		assert.True(t, errors.Is(err, ErrConditionFailed))
+		assert.False(t, errors.Is(err, ErrThrottled))
+	})
+}
+
+func TestHandlerImp_buildTransactWriteItem(t *testing.T) {
+	repo := handlerImp{config: cfg}
+	validDBKeys := NewDbPSKeyValues("part", func() *DBKeyValue {
+		sKey := DBKeyValue("sKey")
+		return &sKey
+	}())
+
+	t.Run("put with condition", func(t *testing.T) {
+		item, err := repo.buildTransactWriteItem(NewTransactPut(
+			TestBaseModel{Name: "golang", SKey: "key"}, false,
+			NewExpressionWrapper(cfg.TableInfo.TableName).WithCondition("Age", 10, GT),
+		))
+		assert.NoError(t, err)
+		assert.NotNil(t, item.Put)
+		assert.NotNil(t, item.Put.ConditionExpression)
+		assert.Equal(t, "golang", aws.StringValue(item.Put.Item[string(pKey)].S))
+	})
+
+	t.Run("put versioned model with condition", func(t *testing.T) {
+		item, err := repo.buildTransactWriteItem(NewTransactPut(
+			NewModel(versionedDoc{ID: "1", Key: "a", Version: 3}), false,
+			NewExpressionWrapper(cfg.TableInfo.TableName).WithCondition("partKey", nil, EXISTS),
+		))
+		assert.NoError(t, err)
+		assert.Equal(t, "4", aws.StringValue(item.Put.Item["version"].N))
+		assert.Equal(t, "(attribute_exists (#0)) AND (#1 = :0)", aws.StringValue(item.Put.ConditionExpression))
+		assert.Equal(t, "version", aws.StringValue(item.Put.ExpressionAttributeNames["#1"]))
+		assert.Equal(t, "3", aws.StringValue(item.Put.ExpressionAttributeValues[":0"].N))
+
+		item, err = repo.buildTransactWriteItem(NewTransactPut(NewModel(versionedDoc{ID: "1", Key: "a"}), false, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", aws.StringValue(item.Put.Item["version"].N))
+		assert.Equal(t, "attribute_not_exists (#0)", aws.StringValue(item.Put.ConditionExpression))
+	})
+
+	t.Run("update with condition", func(t *testing.T) {
+		item, err := repo.buildTransactWriteItem(NewTransactUpdate(validDBKeys,
+			NewExpressionWrapper(cfg.TableInfo.TableName).
+				WithUpdateField("Age", 11).
+				WithCondition("Age", 10, GT),
+		))
+		assert.NoError(t, err)
+		assert.NotNil(t, item.Update)
+		assert.NotNil(t, item.Update.UpdateExpression)
+		assert.NotNil(t, item.Update.ConditionExpression)
+		assert.Equal(t, "part", aws.StringValue(item.Update.Key[string(pKey)].S))
+		assert.Equal(t, "sKey", aws.StringValue(item.Update.Key[string(sKey)].S))
+	})
+
+	t.Run("delete without condition", func(t *testing.T) {
+		item, err := repo.buildTransactWriteItem(NewTransactDelete(validDBKeys, nil))
+		assert.NoError(t, err)
+		assert.NotNil(t, item.Delete)
+		assert.Nil(t, item.Delete.ConditionExpression)
+	})
+
+	t.Run("put without model", func(t *testing.T) {
+		_, err := repo.buildTransactWriteItem(NewTransactPut(nil, true, nil))
+		assert.Error(t, err)
+	})
+}
+
+func TestHandlerImp_TransactGetByIDs(t *testing.T) {
+	validDBKeys := NewDbPSKeyValues("part", func() *DBKeyValue {
+		sKey := DBKeyValue("sKey")
+		return &sKey
+	}())
+	validItem := DBMap{
+		"Name": &dynamodb.AttributeValue{S: aws.String("golang")},
+		"Age":  &dynamodb.AttributeValue{N: aws.String("12")},
+	}
+
+	t.Run("successfully", func(t *testing.T) {
+		repo := handlerImp{
+			config: cfg,
+			DynamoDBAPI: MockedTransactGet{
+				Resp: dynamodb.TransactGetItemsOutput{
+					Responses: []*dynamodb.ItemResponse{
+						{Item: validItem},
+						{},
+					},
+				},
+			},
+		}
+		ctx := context.Background()
+		res, err := repo.TransactGetByIDs(ctx,
+			NewTransactGetItem(TestBaseModel{}, validDBKeys),
+			NewTransactGetItem(TestBaseModel{}, validDBKeys),
+		)
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, "golang", res[0].(TestBaseModel).Name)
+		assert.Nil(t, res[1])
+	})
+
+	t.Run("without items", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockedTransactGet{}}
+		_, err := repo.TransactGetByIDs(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("with missing model", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockedTransactGet{}}
+		_, err := repo.TransactGetByIDs(context.Background(), NewTransactGetItem(nil, validDBKeys))
+		assert.Error(t, err)
+	})
+
+	t.Run("with missing sort key", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockedTransactGet{}}
+		_, err := repo.TransactGetByIDs(context.Background(),
+			NewTransactGetItem(TestBaseModel{}, NewDbPSKeyValues("part", nil)),
+		)
+		assert.Error(t, err)
+	})
+
+	t.Run("with unmarshalling error", func(t *testing.T) {
+		repo := handlerImp{
+			config: cfg,
+			DynamoDBAPI: MockedTransactGet{
+				Resp: dynamodb.TransactGetItemsOutput{
+					Responses: []*dynamodb.ItemResponse{{Item: validItem}},
+				},
+			},
+		}
+		_, err := repo.TransactGetByIDs(context.Background(),
+			NewTransactGetItem(TestBaseModel{withMarshallingErr: true}, validDBKeys),
+		)
+		assert.Error(t, err)
+	})
+
+	t.Run("with canceled transaction", func(t *testing.T) {
+		repo := handlerImp{
+			config: cfg,
+			DynamoDBAPI: MockedTransactGet{
+				Err: &dynamodb.TransactionCanceledException{
+					CancellationReasons: []*dynamodb.CancellationReason{
+						{Code: aws.String("TransactionConflict")},
+					},
+				},
+			},
+		}
+		_, err := repo.TransactGetByIDs(context.Background(), NewTransactGetItem(TestBaseModel{}, validDBKeys))
+		var canceledErr *TransactionCanceledError
+		assert.True(t, errors.As(err, &canceledErr))
+	})
+}
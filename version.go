@@ -0,0 +1,181 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// ErrVersionConflict is matched by errors.Is when a write is rejected because the record version changed
+var ErrVersionConflict = errors.New("version conflict")
+
+// VersionedModel is implemented by models using optimistic locking, the version attribute is a number
+// initialised to 1 by AddRecord and incremented by every update, which is applied only if the stored
+// version still matches the version the model was read with
+type VersionedModel interface {
+	BaseModel
+	// GetVersion returns the version attribute name and the current version, an empty name disables the locking
+	GetVersion() (FieldName, int64)
+}
+
+// ExpectedVersion marks the version attribute in the data of Update eg. data["version"] = ExpectedVersion(3)
+// the update is applied only if the stored version is 3, and sets it to 4
+type ExpectedVersion int64
+
+// VersionConflictError is returned when the stored version does not match the expected version
+type VersionConflictError struct {
+	Name     FieldName
+	Expected int64
+	err      error
+}
+
+// Error returns the error message along with the expected version
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict: %s is not %d", e.Name, e.Expected)
+}
+
+// Is matches ErrVersionConflict and ErrConditionFailed
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict || target == ErrConditionFailed
+}
+
+// Unwrap returns the underlying dynamodb error
+func (e *VersionConflictError) Unwrap() error {
+	return e.err
+}
+
+// versionCheck the version attribute and the version expected by an update
+type versionCheck struct {
+	name     FieldName
+	expected int64
+}
+
+// WithVersion applies optimistic locking to the update, the version attribute is incremented
+// and the update is applied only if the stored version matches expected,
+// a record which was never versioned is matched by the expected version 0
+func (expr *AwsExpressionWrapper) WithVersion(name string, expected int64) *AwsExpressionWrapper {
+	expr.updateExpression = expr.updateExpression.Set(
+		expression.Name(name),
+		expression.Value(versionValue(expected+1)),
+	)
+	expr.version = &versionCheck{name: FieldName(name), expected: expected}
+	return expr
+}
+
+// condition returns the condition matching the expected version
+func (v versionCheck) condition() expression.ConditionBuilder {
+	if v.expected == 0 {
+		return expression.AttributeNotExists(expression.Name(string(v.name)))
+	}
+	return expression.Name(string(v.name)).Equal(expression.Value(versionValue(v.expected)))
+}
+
+// withVersionCondition adds the version condition to the condition using AND, if the version is checked
+func withVersionCondition(condition expression.ConditionBuilder, version *versionCheck) expression.ConditionBuilder {
+	if version == nil {
+		return condition
+	}
+	if reflect.DeepEqual(condition, expression.ConditionBuilder{}) {
+		return version.condition()
+	}
+	return condition.And(version.condition())
+}
+
+// conflictErr translates the failed condition of a versioned write into VersionConflictError,
+// the other errors are translated using translateErr
+func (v *versionCheck) conflictErr(err error) error {
+	var awsErr awserr.Error
+	if err == nil || v == nil || !errors.As(err, &awsErr) ||
+		awsErr.Code() != dynamodb.ErrCodeConditionalCheckFailedException {
+		return translateErr(err)
+	}
+	return &VersionConflictError{Name: v.name, Expected: v.expected, err: err}
+}
+
+// applyToPut increments the version of the item and applies the version condition to the put request
+func (v *versionCheck) applyToPut(input *dynamodb.PutItemInput) error {
+	if v == nil {
+		return nil
+	}
+	condition, err := expression.NewBuilder().WithCondition(v.condition()).Build()
+	if err != nil {
+		return err
+	}
+	input.Item[string(v.name)] = versionValue(v.expected + 1)
+	input.ConditionExpression = condition.Condition()
+	input.ExpressionAttributeNames = condition.Names()
+	input.ExpressionAttributeValues = condition.Values()
+	return nil
+}
+
+// putVersioned writes the versioned records using conditional puts with a bounded number of concurrent calls,
+// returns the records that were not written, along with the first error if any
+func (h handlerImp) putVersioned(ctx context.Context, records []BaseModel) ([]BaseModel, error) {
+	batchCfg := h.config.BatchWrite.withDefaults()
+	tableName := aws.String(h.config.TableInfo.TableName)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	failed := make([]BaseModel, 0)
+	sem := make(chan struct{}, batchCfg.MaxConcurrency)
+
+	for _, rec := range records {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(rec BaseModel) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			item, _, err := h.createPutItem(rec, false, false, false)
+			if err == nil {
+				input := dynamodb.PutItemInput{Item: item, TableName: tableName}
+				version := modelVersion(rec)
+				if err = version.applyToPut(&input); err == nil {
+					_, err = h.PutItemWithContext(ctx, &input)
+					err = version.conflictErr(err)
+				}
+			}
+			if err == nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, rec)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}(rec)
+	}
+
+	wg.Wait()
+	return failed, firstErr
+}
+
+// modelVersion returns the version check of the model, nil if the model is not versioned
+func modelVersion(in BaseModel) *versionCheck {
+	versioned, ok := in.(VersionedModel)
+	if !ok {
+		return nil
+	}
+	name, version := versioned.GetVersion()
+	if name == "" {
+		return nil
+	}
+	return &versionCheck{name: name, expected: version}
+}
+
+// versionValue returns the number attribute value of the version
+func versionValue(version int64) *dynamodb.AttributeValue {
+	return &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(version, 10))}
+}
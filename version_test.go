@@ -0,0 +1,140 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+type versionedDoc struct {
+	ID      string `json:"partKey" dyorm:"pk"`
+	Key     string `json:"sortKey" dyorm:"sk"`
+	Version int64  `json:"version" dyorm:"version"`
+}
+
+type versionedWithInvalidVersion struct {
+	ID      string `dyorm:"pk"`
+	Version string `dyorm:"version"`
+}
+
+var conditionFailedErr = awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil)
+
+func TestModel_GetVersion(t *testing.T) {
+	name, version := NewModel(versionedDoc{ID: "1", Version: 3}).GetVersion()
+	assert.Equal(t, FieldName("version"), name)
+	assert.Equal(t, int64(3), version)
+
+	name, _ = NewModel(taggedUser{ID: "1"}).GetVersion()
+	assert.Empty(t, name)
+	assert.Nil(t, modelVersion(NewModel(taggedUser{ID: "1"})))
+	assert.Nil(t, modelVersion(TestBaseModel{}))
+
+	_, err := RegisterModel[versionedWithInvalidVersion]("doc", "docs")
+	assert.Error(t, err)
+}
+
+func TestVersionCheck_applyToPut(t *testing.T) {
+	t.Run("expected version", func(t *testing.T) {
+		input := dynamodb.PutItemInput{Item: DBMap{}}
+		assert.NoError(t, (&versionCheck{name: "version", expected: 3}).applyToPut(&input))
+		assert.Equal(t, "4", aws.StringValue(input.Item["version"].N))
+		assert.Equal(t, "#0 = :0", aws.StringValue(input.ConditionExpression))
+		assert.Equal(t, "version", aws.StringValue(input.ExpressionAttributeNames["#0"]))
+		assert.Equal(t, "3", aws.StringValue(input.ExpressionAttributeValues[":0"].N))
+	})
+
+	t.Run("never versioned", func(t *testing.T) {
+		input := dynamodb.PutItemInput{Item: DBMap{}}
+		assert.NoError(t, (&versionCheck{name: "version"}).applyToPut(&input))
+		assert.Equal(t, "1", aws.StringValue(input.Item["version"].N))
+		assert.Equal(t, "attribute_not_exists (#0)", aws.StringValue(input.ConditionExpression))
+	})
+
+	t.Run("not versioned", func(t *testing.T) {
+		var version *versionCheck
+		input := dynamodb.PutItemInput{Item: DBMap{}}
+		assert.NoError(t, version.applyToPut(&input))
+		assert.Nil(t, input.ConditionExpression)
+	})
+}
+
+func TestAwsExpressionWrapper_WithVersion(t *testing.T) {
+	input, err := NewExpressionWrapper("table").
+		WithPartitionKey("partKey", "1").
+		WithUpdateField("name", "golang").
+		WithCondition("partKey", nil, EXISTS).
+		WithVersion("version", 2).
+		BuildUpdateInput()
+	assert.NoError(t, err)
+	// the condition is numbered first
+	assert.Equal(t, "(attribute_exists (#0)) AND (#1 = :0)", aws.StringValue(input.ConditionExpression))
+	assert.Equal(t, "SET #2 = :1, #1 = :2\n", aws.StringValue(input.UpdateExpression))
+	assert.Equal(t, "2", aws.StringValue(input.ExpressionAttributeValues[":0"].N))
+	assert.Equal(t, "3", aws.StringValue(input.ExpressionAttributeValues[":2"].N))
+}
+
+func TestHandlerImp_VersionedWrites(t *testing.T) {
+	doc := NewModel(versionedDoc{ID: "1", Key: "a", Version: 3})
+	keys := doc.GetPartSortKey(nil)
+	ctx := context.Background()
+
+	t.Run("add initialises the version", func(t *testing.T) {
+		item, _, err := handlerImp{config: cfg}.createPutItem(doc, true, false, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", aws.StringValue(item["version"].N))
+	})
+
+	t.Run("update record with version conflict", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockedPutItem{Err: conditionFailedErr}}
+		err := repo.UpdateRecordByID(ctx, doc, keys)
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+
+		var conflictErr *VersionConflictError
+		assert.True(t, errors.As(err, &conflictErr))
+		assert.Equal(t, int64(3), conflictErr.Expected)
+	})
+
+	t.Run("update record with other errors", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockedPutItem{Err: errors.New("db error")}}
+		err := repo.UpdateRecordByID(ctx, doc, keys)
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrVersionConflict))
+
+		// the condition of non versioned models is not a version conflict
+		repo = handlerImp{config: cfg, DynamoDBAPI: MockedPutItem{Err: conditionFailedErr}}
+		err = repo.UpdateRecordByID(ctx, TestBaseModel{Name: "golang"}, keys)
+		assert.False(t, errors.Is(err, ErrVersionConflict))
+	})
+
+	t.Run("update with expected version", func(t *testing.T) {
+		repo := handlerImp{config: cfg, DynamoDBAPI: MockedUpdateItem{Err: conditionFailedErr}}
+		sortKey := "a"
+		err := repo.Update(ctx, "1", &sortKey, map[FieldName]interface{}{
+			"name":    "golang",
+			"version": ExpectedVersion(3),
+		})
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+
+		err = repo.UpdateByID(ctx, keys, NewExpressionWrapper("table").WithVersion("version", 3))
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+	})
+
+	t.Run("bulk update", func(t *testing.T) {
+		other := NewModel(versionedDoc{ID: "2", Key: "b", Version: 1})
+
+		repo := handlerImp{config: bulkCfg, DynamoDBAPI: MockedPutItem{}}
+		failed, err := repo.BulkUpdateRecords(ctx, doc, doc, other)
+		assert.NoError(t, err)
+		assert.Empty(t, failed)
+
+		repo = handlerImp{config: bulkCfg, DynamoDBAPI: MockedPutItem{Err: conditionFailedErr}}
+		failed, err = repo.BulkUpdateRecords(ctx, doc, doc, other)
+		assert.True(t, errors.Is(err, ErrVersionConflict))
+		assert.Len(t, failed, 2)
+	})
+}